@@ -0,0 +1,191 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/bundle"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle [file]",
+	Short: "Inline local library code into a single file for submission",
+	Long: `Creates a single submittable file from a solution that uses a local library.
+
+For C++, every quoted include (#include "lib/segtree.hpp") is replaced by the contents of the
+header, looked up next to the including file and then in the configured 'includePaths'.
+Headers are expanded recursively; ones marked with #pragma once or protected by an
+include guard are inlined only once. System headers (#include <...>) are kept as they are.
+
+For Python, every module imported from the script's directory or the configured
+'libraryDir', directly or by another local module, is embedded into the script. The
+modules are still imported the usual way, so 'if __name__ == "__main__"' blocks behave
+as before.
+
+The result is written to submit.<language> next to the program file, 'main.<language>'
+in the current directory unless a file is given. A C++ bundle is then compiled with
+'buildCommand' to make sure it builds, and a Python bundle is run against the problem's
+tests to make sure it behaves like the original; use --no-check to skip this. The check
+runs on a copy in a temporary directory, which is also where {{.Dir}} points, so the
+program's own build output is left alone.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		language := viper.GetString("language")
+		programPath := fmt.Sprintf("%s.%s", viper.GetString("programFile"), language)
+		if len(args) == 1 {
+			programPath = args[0]
+			language = strings.TrimPrefix(filepath.Ext(programPath), ".")
+		}
+		programPath, err := filepath.Abs(programPath)
+		if err != nil {
+			return err
+		}
+
+		stripComments := viper.GetBool("bundleStripComments")
+		if flag := cmd.Flags().Lookup("strip-comments"); flag.Changed {
+			stripComments, _ = cmd.Flags().GetBool("strip-comments")
+		}
+
+		submitPath, err := bundleProgram(programPath, language, stripComments)
+		if err != nil {
+			return err
+		}
+		fmt.Println(submitPath)
+
+		if noCheck, _ := cmd.Flags().GetBool("no-check"); noCheck {
+			return nil
+		}
+		return checkBundle(cmd, filepath.Dir(programPath), submitPath, language == "py")
+	},
+}
+
+// bundleProgram writes the bundle of the program at programPath to
+// submit.<language> next to it and returns its path.
+func bundleProgram(programPath, language string, stripComments bool) (string, error) {
+	var bundled []byte
+	var err error
+	switch language {
+	case "cpp", "cc", "cxx":
+		b := bundle.NewCPPBundler(viper.GetStringSlice("includePaths"), stripComments, logger.Std())
+		bundled, err = b.Bundle(programPath)
+	case "py":
+		b := bundle.NewPythonBundler(viper.GetString("libraryDir"), logger.Std())
+		bundled, err = b.Bundle(programPath)
+	default:
+		return "", fmt.Errorf("bundling %q files is not supported", language)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	submitPath := filepath.Join(filepath.Dir(programPath), "submit."+language)
+	if err := os.WriteFile(submitPath, bundled, 0o644); err != nil {
+		return "", err
+	}
+	return submitPath, nil
+}
+
+// checkBundle builds the bundled file with the configured buildCommand and,
+// with runTests, runs it against the tests in dir. The check works on a copy
+// in a temporary directory, so a build with a fixed output such as -o main
+// leaves the program's own binary alone.
+func checkBundle(cmd *cobra.Command, dir, submitPath string, runTests bool) error {
+	checkDir, err := os.MkdirTemp("", "codeforces-cli-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(checkDir)
+
+	bundled, err := os.ReadFile(submitPath)
+	if err != nil {
+		return err
+	}
+	checkPath := filepath.Join(checkDir, filepath.Base(submitPath))
+	if err := os.WriteFile(checkPath, bundled, 0o644); err != nil {
+		return err
+	}
+
+	variables := map[string]string{
+		"Path": checkPath,
+		"Dir":  checkDir,
+	}
+
+	renderedBuild, err := renderCommand("build", viper.GetString("buildCommand"), variables)
+	if err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+	renderedExec, err := renderCommand("exec", viper.GetString("executeCommand"), variables)
+	if err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+
+	em := execution.NewEngine(
+		checkDir,
+		dir,
+		renderedBuild,
+		renderedExec,
+		viper.GetString("testCaseInputPrefix"),
+		viper.GetString("testCaseOutputPrefix"),
+		logger.Std(),
+	)
+
+	var buildErr *execution.BuildError
+	if !runTests {
+		if renderedBuild == "" {
+			return nil
+		}
+		if err := em.Build(); errors.As(err, &buildErr) {
+			cmd.SilenceErrors = true
+			return withExitCode(exitCompileError, nil)
+		} else if err != nil {
+			return err
+		}
+		logger.Infof("%s compiles", filepath.Base(submitPath))
+		return nil
+	}
+
+	checker, err := execution.ParseChecker(viper.GetString("checker"))
+	if err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+	em.SetChecker(checker)
+	em.SetTimeLimit(time.Duration(viper.GetInt("timeLimit")) * time.Millisecond)
+
+	res, err := em.Execute()
+	if errors.As(err, &buildErr) {
+		cmd.SilenceErrors = true
+		return withExitCode(exitCompileError, nil)
+	} else if err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		logger.Warnf("No tests to run %s against", filepath.Base(submitPath))
+		return nil
+	}
+	if code := resultsExitCode(res); code != 0 {
+		printResults(res)
+		cmd.SilenceErrors = true
+		return withExitCode(code, nil)
+	}
+	logger.Infof("%s passes all %d test(s)", filepath.Base(submitPath), len(res))
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+
+	bundleCmd.Flags().Bool("strip-comments", false, "remove comments from a bundled C++ file (default is bundleStripComments)")
+	bundleCmd.Flags().Bool("no-check", false, "don't compile or test the bundled file")
+}
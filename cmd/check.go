@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/lint"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check [file]",
+	Short: "Look for debugging leftovers before submitting",
+	Long: `Scans the program file, 'main.<language>' in the current directory unless a file is
+given, for mistakes that commonly cost a submission:
+
+  cerr             debug output to cerr outside #ifdef LOCAL
+  freopen          freopen of local files outside #ifdef LOCAL
+  int-scanf        scanf or printf with %d while int is defined as long long
+  sync-with-stdio  cin or cout without ios::sync_with_stdio(false)
+  stderr-print     leftover output to sys.stderr in Python
+
+'lintRules' selects the rules to run (all by default). Set 'lintAfterExecute' to run the
+check after every 'execute' in which all tests pass. The command exits with status 1
+when it finds anything.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		programPath := fmt.Sprintf("%s.%s", viper.GetString("programFile"), viper.GetString("language"))
+		if len(args) == 1 {
+			programPath = args[0]
+		}
+
+		findings, err := lintProgram(programPath)
+		if err != nil {
+			return err
+		}
+		if len(findings) == 0 {
+			color.Green("No problems found in %s", programPath)
+			return nil
+		}
+
+		for _, f := range findings {
+			fmt.Printf("%s:%s\n", programPath, f)
+		}
+		cmd.SilenceErrors = true
+		return withExitCode(1, nil)
+	},
+}
+
+// lintProgram runs the configured lint rules on the program at path, using its
+// extension as the language.
+func lintProgram(path string) ([]lint.Finding, error) {
+	rules, err := lint.ParseRules(viper.GetStringSlice("lintRules"))
+	if err != nil {
+		return nil, withExitCode(exitConfigError, err)
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return lint.Lint(strings.TrimPrefix(filepath.Ext(path), "."), src, rules), nil
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
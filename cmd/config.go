@@ -0,0 +1,321 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// boundFlags records the flags bound to configuration keys, so that
+// 'config show --origin' can tell when a value came from the command line.
+var boundFlags = map[string]*pflag.Flag{}
+
+func bindFlag(key string, flag *pflag.Flag) {
+	boundFlags[key] = flag
+	cobra.CheckErr(viper.BindPFlag(key, flag))
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Create, inspect and change the configuration",
+	Long: `Manages the configuration file.
+
+  config init            interactively create a config file with detected compilers
+  config get <key>       print the effective value of a key
+  config set <key> <v>   write a key to the config file
+  config show [--origin] print every effective value and where it came from
+
+Secrets such as apiSecret are printed as **** unless --reveal is given.`,
+	Annotations: map[string]string{skipConfigValidation: "true"},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Interactively create a config file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		acceptDefaults, _ := cmd.Flags().GetBool("yes")
+
+		path := configWritePath()
+		if _, err := os.Stat(path); err == nil && !force {
+			cobra.CheckErr(fmt.Errorf("%s already exists (use --force to replace it)", path))
+		}
+
+		in := bufio.NewReader(os.Stdin)
+		ask := func(question, def string) string {
+			if acceptDefaults {
+				return def
+			}
+			fmt.Printf("%s [%s]: ", question, def)
+			answer, _ := in.ReadString('\n')
+			if answer = strings.TrimSpace(answer); answer == "" {
+				return def
+			}
+			return answer
+		}
+
+		presets := detectLanguagePresets()
+		if len(presets) == 0 {
+			fmt.Println("No known compiler or interpreter found on PATH; using the Python defaults.")
+			presets = []languagePreset{pythonPreset("python3")}
+		}
+		fmt.Println("Detected toolchains:")
+		for i, p := range presets {
+			fmt.Printf("  %d) %s\n", i+1, p.name)
+		}
+		choice, err := strconv.Atoi(ask("Toolchain", "1"))
+		if err != nil || choice < 1 || choice > len(presets) {
+			cobra.CheckErr(fmt.Errorf("invalid toolchain choice"))
+		}
+		preset := presets[choice-1]
+
+		v := viper.New()
+		v.Set("root", ask("Problems directory (root)", viper.GetString("root")))
+		v.Set("language", preset.language)
+		v.Set("programFile", viper.GetString("programFile"))
+		v.Set("buildCommand", ask("Build command", preset.buildCommand))
+		v.Set("executeCommand", ask("Execute command", preset.executeCommand))
+		v.Set("editorCommand", ask("Editor command", detectEditorCommand()))
+		v.Set("templatePath", ask("Template file (empty for none)", ""))
+
+		port, err := strconv.Atoi(ask("Competitive Companion port", viper.GetString("port")))
+		cobra.CheckErr(err)
+		v.Set("port", port)
+
+		cobra.CheckErr(os.MkdirAll(filepath.Dir(path), 0o755))
+		cobra.CheckErr(v.WriteConfigAs(path))
+		fmt.Println("Wrote", path)
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a configuration key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := args[0]
+		if !isConfigKey(key) {
+			cobra.CheckErr(fmt.Errorf("unknown configuration key %q", key))
+		}
+		reveal, _ := cmd.Flags().GetBool("reveal")
+		fmt.Println(configValue(key, reveal))
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Write a configuration key to the config file",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, raw := args[0], args[1]
+		if !isConfigKey(key) {
+			cobra.CheckErr(fmt.Errorf("unknown configuration key %q", key))
+		}
+
+		value, err := parseConfigValue(key, raw)
+		cobra.CheckErr(err)
+
+		// Refuse values that would leave the configuration invalid.
+		viper.Set(key, value)
+		cobra.CheckErr(validateConfig())
+
+		path, err := writeConfigKey(key, value)
+		cobra.CheckErr(err)
+		fmt.Printf("%s = %v (%s)\n", key, configValue(key, false), path)
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		withOrigin, _ := cmd.Flags().GetBool("origin")
+		reveal, _ := cmd.Flags().GetBool("reveal")
+		printConfig(os.Stdout, withOrigin, reveal)
+	},
+}
+
+func printConfig(w io.Writer, withOrigin, reveal bool) {
+	keys := viper.AllKeys()
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if withOrigin {
+			fmt.Fprintf(w, "%s: %v\t(%s)\n", key, configValue(key, reveal), configOrigin(key))
+		} else {
+			fmt.Fprintf(w, "%s: %v\n", key, configValue(key, reveal))
+		}
+	}
+}
+
+// secretKeys are the configuration keys whose values are masked when
+// printed, as viper lists them.
+var secretKeys = []string{"apisecret"}
+
+// configValue returns the effective value of key for printing, with a set
+// secret masked unless reveal is true.
+func configValue(key string, reveal bool) any {
+	if !reveal && slices.Contains(secretKeys, strings.ToLower(key)) && viper.GetString(key) != "" {
+		return "****"
+	}
+	return viper.Get(key)
+}
+
+// configOrigin reports where the effective value of key comes from, following
+// viper's precedence: flag, environment, override file, config file, default.
+func configOrigin(key string) string {
+	if flag, ok := boundFlags[key]; ok && flag.Changed {
+		return "flag --" + flag.Name
+	}
+	if _, ok := os.LookupEnv(strings.ToUpper(key)); ok {
+		return "env " + strings.ToUpper(key)
+	}
+	if path, ok := overrideOrigins[key]; ok {
+		return "directory " + path
+	}
+	if viper.InConfig(key) {
+		return "file " + viper.ConfigFileUsed()
+	}
+	return "default"
+}
+
+func isConfigKey(key string) bool {
+	return slices.Contains(viper.AllKeys(), strings.ToLower(key))
+}
+
+// parseConfigValue converts raw to the type of the key's current value.
+func parseConfigValue(key, raw string) (any, error) {
+	switch viper.Get(key).(type) {
+	case int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects a number: %w", key, err)
+		}
+		return n, nil
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects true or false: %w", key, err)
+		}
+		return b, nil
+	case []string, []any:
+		var list []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	default:
+		return raw, nil
+	}
+}
+
+// writeConfigKey sets key in the config file and returns the file's path.
+// It writes through a viper holding only the file's content, so defaults
+// and environment overrides are not copied into the file.
+func writeConfigKey(key string, value any) (string, error) {
+	path := configWritePath()
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	v.Set(key, value)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return path, v.WriteConfigAs(path)
+}
+
+// configWritePath is the file config commands write to: the one given with
+// --config or in use, otherwise the primary location.
+func configWritePath() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	if used := viper.ConfigFileUsed(); used != "" {
+		if _, err := os.Stat(used); err == nil {
+			return used
+		}
+	}
+	configDir, err := os.UserConfigDir()
+	cobra.CheckErr(err)
+	return filepath.Join(configDir, "codeforces-cli", "config.yaml")
+}
+
+type languagePreset struct {
+	name           string
+	language       string
+	buildCommand   string
+	executeCommand string
+}
+
+func pythonPreset(interpreter string) languagePreset {
+	return languagePreset{
+		name:           "Python (" + interpreter + ")",
+		language:       "py",
+		executeCommand: interpreter + " {{.Path}}",
+	}
+}
+
+// detectLanguagePresets offers a preset for every known toolchain on PATH.
+func detectLanguagePresets() []languagePreset {
+	var presets []languagePreset
+	for _, compiler := range []string{"g++", "clang++"} {
+		if _, err := exec.LookPath(compiler); err == nil {
+			presets = append(presets, languagePreset{
+				name:           "C++ (" + compiler + ")",
+				language:       "cpp",
+				buildCommand:   compiler + " -std=c++17 -O2 -o {{.Dir}}/main {{.Path}}",
+				executeCommand: "{{.Dir}}/main",
+			})
+		}
+	}
+	for _, interpreter := range []string{"python3", "pypy3"} {
+		if _, err := exec.LookPath(interpreter); err == nil {
+			presets = append(presets, pythonPreset(interpreter))
+		}
+	}
+	return presets
+}
+
+func detectEditorCommand() string {
+	for _, editor := range []string{"nvim", "vim", "code", "nano"} {
+		if _, err := exec.LookPath(editor); err == nil {
+			return editor + " {{.Path}}"
+		}
+	}
+	return viper.GetString("editorCommand")
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd, configGetCmd, configSetCmd, configShowCmd)
+
+	configInitCmd.Flags().Bool("force", false, "replace an existing config file")
+	configInitCmd.Flags().BoolP("yes", "y", false, "accept all defaults without prompting")
+	configShowCmd.Flags().Bool("origin", false, "show where each value comes from (default, file, env or flag)")
+	configShowCmd.Flags().Bool("reveal", false, "print secrets such as apiSecret instead of masking them")
+	configGetCmd.Flags().Bool("reveal", false, "print a secret such as apiSecret instead of masking it")
+}
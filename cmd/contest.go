@@ -0,0 +1,174 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/dashboard"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/editor"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// contestCmd represents the contest command
+var contestCmd = &cobra.Command{
+	Use:   "contest <id>",
+	Short: "Show a dashboard of the imported problems of a contest",
+	Long: `Shows every imported problem of the contest with the result and time of its last
+test run and whether the program changed since. The view refreshes whenever the
+problems' files change, including problems imported while it is open.
+
+Keys:
+  ↑/↓, k/j   select a problem
+  r, Enter   run the tests of the selected problem (see 'execute')
+  e          open the selected problem in the editor
+  b          bundle the selected problem (see 'bundle')
+  q          quit`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contestID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid contest id %q", args[0])
+		}
+
+		dm, err := newDirectoryManager()
+		if err != nil {
+			return err
+		}
+		launcher, err := newEditorLauncher()
+		if err != nil {
+			return err
+		}
+		programFile := fmt.Sprintf("%s.%s", viper.GetString("programFile"), viper.GetString("language"))
+
+		load := func() ([]dashboard.Row, error) {
+			entries, err := dm.ListProblems()
+			if err != nil {
+				return nil, err
+			}
+			var rows []dashboard.Row
+			for _, entry := range entries {
+				if entry.Problem.ContestCode == contestID {
+					rows = append(rows, dashboard.NewRow(entry, programFile))
+				}
+			}
+			sort.Slice(rows, func(i, j int) bool { return rows[i].Index < rows[j].Index })
+			return rows, nil
+		}
+
+		// Log messages would scramble the screen.
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		if !verbose {
+			logger.SetLevel(logging.LevelError)
+		}
+
+		d := dashboard.New(dashboard.Options{
+			Title: fmt.Sprintf("Contest %d", contestID),
+			Root:  viper.GetString("root"),
+			Load:  load,
+			Actions: dashboard.Actions{
+				Run: func(row dashboard.Row) (string, error) {
+					code, _, err := runSelf(row.Dir, "execute")
+					if err != nil {
+						return "", err
+					}
+					return exitStatusText(code), nil
+				},
+				Bundle: func(row dashboard.Row) (string, error) {
+					code, out, err := runSelf(row.Dir, "bundle")
+					if err != nil {
+						return "", err
+					}
+					if code != 0 {
+						return exitStatusText(code), nil
+					}
+					return "bundled into " + filepath.Base(strings.TrimSpace(out)), nil
+				},
+				Edit: func(row dashboard.Row) error {
+					if p, err := dm.ProblemKeyForDir(row.Dir); err == nil {
+						markOpened(p)
+					}
+					return launcher.Open(editor.Vars{Path: row.Program, Dir: row.Dir, Tests: testInputPaths(row.Dir)})
+				},
+			},
+			ForegroundEditor: editor.Mode(viper.GetString("editorMode")) == editor.ModeForeground,
+		}, logger.Std())
+		return d.Run()
+	},
+}
+
+// runSelf runs another codeforces-cli command in dir, so the directory's
+// override files apply to it, and returns its exit status and first line of
+// output. Failures other than the test verdicts are returned as errors
+// carrying the command's last message.
+func runSelf(dir string, args ...string) (int, string, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return 0, "", err
+	}
+	if cfgFile != "" {
+		args = append([]string{"--config", cfgFile}, args...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	c := exec.Command(self, append([]string{"--quiet"}, args...)...)
+	c.Dir = dir
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	err = c.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		switch code {
+		case exitWrongAnswer, exitCompileError, exitRuntimeError:
+			return code, "", nil
+		}
+		if msg := lastLine(stderr.String()); msg != "" {
+			return code, "", errors.New(strings.TrimPrefix(msg, "Error: "))
+		}
+		return code, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	line, _, _ := strings.Cut(stdout.String(), "\n")
+	return 0, line, nil
+}
+
+// exitStatusText describes the documented exit statuses.
+func exitStatusText(code int) string {
+	switch code {
+	case 0:
+		return "all tests passed"
+	case exitWrongAnswer:
+		return "wrong answer"
+	case exitCompileError:
+		return "compile error"
+	case exitRuntimeError:
+		return "runtime error or time limit exceeded"
+	case exitConfigError:
+		return "configuration error"
+	}
+	return fmt.Sprintf("exit status %d", code)
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func init() {
+	rootCmd.AddCommand(contestCmd)
+}
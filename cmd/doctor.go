@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/doctor"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the configuration and environment",
+	Long: `Validates every configuration value and checks the environment it relies on:
+the compilers, interpreters and editor referenced by the commands are on PATH,
+'root' is writable, 'templatePath' is readable and 'port' is free.
+
+A pass/fail report with hints on how to fix each problem is printed.`,
+	Annotations: map[string]string{skipConfigValidation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := doctor.Diagnose(configSettings())
+		printChecks(checks)
+
+		if doctor.Failed(checks) {
+			cobra.CheckErr(fmt.Errorf("configuration has problems"))
+		}
+	},
+}
+
+func printChecks(checks []doctor.Check) {
+	for _, c := range checks {
+		switch c.Status {
+		case doctor.Pass:
+			fmt.Printf("%s %s: %s\n", color.GreenString("✔"), c.Name, c.Message)
+		case doctor.Warn:
+			fmt.Printf("%s %s: %s\n", color.YellowString("!"), c.Name, c.Message)
+		case doctor.Fail:
+			fmt.Printf("%s %s: %s\n", color.RedString("✘"), c.Name, c.Message)
+		}
+		if c.Status != doctor.Pass && c.Hint != "" {
+			fmt.Printf("    hint: %s\n", c.Hint)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -5,12 +5,18 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"text/template"
+	"time"
 
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
 	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/report"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -22,12 +28,36 @@ var executeCmd = &cobra.Command{
 	Short: "Run test cases for a problem",
 	Long: `Executes all test cases defined for a problem within its directory.
 
-To use this command, navigate your terminal to the problem's directory, typically located at /<contest>/<problem_code>. 
+To use this command, navigate your terminal to the problem's directory, located below 'root' as described by the configured 'layout' (by default /<contest>/<problem_code>).
 
 The command utilizes the 'buildCommand' specified in the configuration to compile the program and the 'executeCommand' to run the compiled executable.
 
-It processes all test files within the directory, comparing the program's output to the expected results. A summary report is then displayed, indicating the number of passed and failed test cases. For failed cases, both the expected output and the program's output are shown for debugging.`,
-	Run: func(cmd *cobra.Command, args []string) {
+It processes all test files within the directory, comparing the program's output to the expected results. A summary report is then displayed, indicating the number of passed and failed test cases. For failed cases, both the expected output and the program's output are shown for debugging.
+
+Use --format json, junit or tap to print the results in a machine-readable form instead.
+
+A run is killed and reported as TLE once it exceeds 'timeLimit' milliseconds, if set.
+
+With --split-multitest, a failed test whose input starts with the number of test cases t
+is split into its cases, each run on its own to report the ones failing with their input.
+Where a case ends is guessed unless 'multitestInputLines' (and 'multitestOutputLines' for
+the expected output) give the number of lines per case.
+
+Exit status:
+  0  all tests passed
+  1  wrong answer
+  2  compile error
+  3  runtime error or time limit exceeded
+  4  configuration error, including a compiler or interpreter that can't be run
+  5  no tests found
+  6  any other error, e.g. invalid arguments or a file that can't be read`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formatStr, _ := cmd.Flags().GetString("format")
+		format, err := report.ParseFormat(formatStr)
+		if err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+
 		rootPath := viper.GetString("root")
 		buildCommand := viper.GetString("buildCommand")
 		executionCommand := viper.GetString("executeCommand")
@@ -37,7 +67,9 @@ It processes all test files within the directory, comparing the program's output
 		language := viper.GetString("language")
 
 		testCasesDir, err := os.Getwd()
-		cobra.CheckErr(err)
+		if err != nil {
+			return withExitCode(exitFailure, err)
+		}
 
 		pathStr := filepath.Join(testCasesDir, fmt.Sprintf("%s.%s", programFile, language))
 
@@ -46,57 +78,210 @@ It processes all test files within the directory, comparing the program's output
 			"Dir":  testCasesDir,
 		}
 
-		buildTemplate, err := template.New("build").Parse(buildCommand)
+		renderedBuild, err := renderCommand("build", buildCommand, variables)
 		if err != nil {
-			logger.Printf("Invalid buildCommand template: %v", err)
+			return withExitCode(exitConfigError, err)
 		}
 
-		var buildCmdBuf bytes.Buffer
-		err = buildTemplate.Execute(&buildCmdBuf, variables)
-		cobra.CheckErr(err)
-
-		execTemplate, err := template.New("exec").Parse(executionCommand)
+		renderedExec, err := renderCommand("exec", executionCommand, variables)
 		if err != nil {
-			logger.Printf("Invalid execCommand template: %v", err)
+			return withExitCode(exitConfigError, err)
 		}
 
-		var execCmdBuf bytes.Buffer
-		err = execTemplate.Execute(&execCmdBuf, variables)
-		cobra.CheckErr(err)
-
-		fmt.Println(execCmdBuf.String())
+		logger.Debugf("Execute command: %s", renderedExec)
 
 		em := execution.NewEngine(
 			rootPath,
 			testCasesDir,
-			buildCmdBuf.String(),
-			execCmdBuf.String(),
+			renderedBuild,
+			renderedExec,
 			inputPrefix,
 			outputPrefix,
-			logger,
+			logger.Std(),
 		)
 
+		checker, err := execution.ParseChecker(viper.GetString("checker"))
+		if err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		em.SetChecker(checker)
+		em.SetTimeLimit(time.Duration(viper.GetInt("timeLimit")) * time.Millisecond)
+
+		if split, _ := cmd.Flags().GetBool("split-multitest"); split {
+			m := &execution.Multitest{
+				InputLines:  viper.GetInt("multitestInputLines"),
+				OutputLines: viper.GetInt("multitestOutputLines"),
+			}
+			if m.InputLines < 0 || m.OutputLines < 0 {
+				return withExitCode(exitConfigError, fmt.Errorf("multitestInputLines and multitestOutputLines must not be negative"))
+			}
+			em.SetMultitest(m)
+		}
+
 		res, err := em.Execute()
-		cobra.CheckErr(err)
+		var buildErr *execution.BuildError
+		if errors.As(err, &buildErr) && !startFailure(buildErr.Err) {
+			recordRun(testCasesDir, directorymanager.RunRecord{At: time.Now(), Verdict: string(execution.VerdictCompileError)})
+			if format != report.FormatText {
+				if err := report.WriteBuildFailure(os.Stdout, format, currentProblemName(testCasesDir), err); err != nil {
+					return withExitCode(exitFailure, err)
+				}
+			}
+			cmd.SilenceErrors = true
+			return withExitCode(exitCompileError, nil)
+		}
+		if err != nil {
+			// Not a verdict: the compiler or program could not be run at all,
+			// e.g. a missing interpreter.
+			return withExitCode(exitConfigError, err)
+		}
+
+		recordRun(testCasesDir, runRecord(res))
+
+		if format == report.FormatText {
+			printResults(res)
+		} else if err := report.Write(os.Stdout, format, currentProblemName(testCasesDir), res); err != nil {
+			return withExitCode(exitFailure, err)
+		}
 
-		printResults(res)
+		if code := resultsExitCode(res); code == exitNoTests {
+			return withExitCode(code, fmt.Errorf("no tests found in %s", testCasesDir))
+		} else if code != 0 {
+			cmd.SilenceErrors = true
+			return withExitCode(code, nil)
+		}
+
+		if viper.GetBool("lintAfterExecute") {
+			findings, err := lintProgram(pathStr)
+			if err != nil {
+				return withExitCode(exitFailure, err)
+			}
+			for _, f := range findings {
+				logger.Warnf("%s:%s", filepath.Base(pathStr), f)
+			}
+		}
+		return nil
 	},
 }
 
+// renderCommand fills a buildCommand or executeCommand template with the
+// program's Path and Dir.
+func renderCommand(name, command string, variables map[string]string) (string, error) {
+	t, err := template.New(name).Parse(command)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, variables); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// startFailure reports whether err means a command could not be started,
+// as opposed to having run and failed.
+func startFailure(err error) bool {
+	var exitErr *exec.ExitError
+	return !errors.As(err, &exitErr)
+}
+
+// resultsExitCode returns the exit status for a test run: runtime errors and
+// timeouts take precedence over wrong answers, and a run without tests
+// doesn't pass.
+func resultsExitCode(results []execution.Result) int {
+	if len(results) == 0 {
+		return exitNoTests
+	}
+	code := 0
+	for _, result := range results {
+		switch result.Verdict {
+		case execution.VerdictRuntimeError, execution.VerdictTimeLimit:
+			return exitRuntimeError
+		case execution.VerdictWrongAnswer:
+			code = exitWrongAnswer
+		}
+	}
+	return code
+}
+
+// runRecord summarizes a test run; the verdict is the most severe one of
+// the tests, runtime errors and timeouts before wrong answers.
+func runRecord(results []execution.Result) directorymanager.RunRecord {
+	record := directorymanager.RunRecord{At: time.Now(), Verdict: string(execution.VerdictOK), Total: len(results)}
+	for _, result := range results {
+		switch result.Verdict {
+		case execution.VerdictOK:
+			record.Passed++
+		case execution.VerdictWrongAnswer:
+			if record.Verdict == string(execution.VerdictOK) {
+				record.Verdict = string(result.Verdict)
+			}
+		default:
+			if record.Verdict != string(execution.VerdictRuntimeError) && record.Verdict != string(execution.VerdictTimeLimit) {
+				record.Verdict = string(result.Verdict)
+			}
+		}
+	}
+	return record
+}
+
+// recordRun stores the outcome of a test run in the problem.json of the
+// problem in dir, if dir holds one, stamping it for a running virtual contest.
+func recordRun(dir string, record directorymanager.RunRecord) {
+	dm, err := newDirectoryManager()
+	if err != nil {
+		return
+	}
+	p, err := dm.ProblemKeyForDir(dir)
+	if err != nil {
+		logger.Debugf("Not recording the run: %v", err)
+		return
+	}
+	err = dm.UpdateMetadata(p, func(meta *directorymanager.Metadata) {
+		meta.LastRun = &record
+		passed := record.Verdict == string(execution.VerdictOK) && record.Total > 0
+		if passed && meta.PassedAt == nil {
+			meta.PassedAt = &record.At
+		}
+		stampVirtual(p, meta, passed)
+	})
+	if err != nil {
+		logger.Warnf("Failed to record the run in problem.json: %v", err)
+	}
+}
+
+// currentProblemName names the problem in dir for reports, e.g. "1234A",
+// falling back to the directory name outside a known problem.
+func currentProblemName(dir string) string {
+	dm, err := newDirectoryManager()
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	p, err := dm.ProblemKeyForDir(dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	return p.ProblemCode
+}
+
 func printResults(results []execution.Result) {
 	passedCount := 0
 	failedCount := 0
 
 	for _, result := range results {
 		if result.Ok {
-			color.Green("Test Case %d: Passed", result.TestCase)
+			color.Green("Test Case %s: Passed", result.Name())
 			passedCount++
 		} else {
-			color.Red("Test Case %d: Failed", result.TestCase)
+			color.Red("Test Case %s: Failed (%s)", result.Name(), result.Verdict)
 			fmt.Println(color.YellowString("Expected Output:"))
 			fmt.Println(result.ExpectedOutput)
 			fmt.Println(color.YellowString("Program Output:"))
 			fmt.Println(result.ProgramOutput)
+			if result.Split != nil {
+				printSplit(result.Split)
+			}
 			failedCount++
 		}
 		fmt.Println("---") // Separator
@@ -106,9 +291,30 @@ func printResults(results []execution.Result) {
 	fmt.Printf("Passed: %d, Failed: %d, Total: %d\n", passedCount, failedCount, len(results))
 }
 
+// printSplit shows the cases of a failed multi-case test that failed on
+// their own.
+func printSplit(split *execution.Split) {
+	if len(split.Failed) == 0 {
+		color.Yellow("All %d cases pass on their own: is state kept between cases?", split.Total)
+		return
+	}
+	for _, sub := range split.Failed {
+		color.Red("Case %d of %d: Failed (%s)", sub.Index, split.Total, sub.Verdict)
+		fmt.Println(color.YellowString("Input:"))
+		fmt.Print(sub.Input)
+		fmt.Println(color.YellowString("Expected Output:"))
+		fmt.Print(sub.ExpectedOutput)
+		fmt.Println(color.YellowString("Program Output:"))
+		fmt.Println(strings.TrimRight(sub.ProgramOutput, "\n"))
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(executeCmd)
 
+	executeCmd.Flags().String("format", "text", "output format: text, json, junit or tap")
+	executeCmd.Flags().Bool("split-multitest", false, "split failed tests starting with a number of test cases into their cases")
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/importer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <problem.json|->...",
+	Short: "Import a problem from a saved Competitive Companion payload",
+	Long: `Imports problems from Competitive Companion JSON payloads without running the listen server.
+
+Each argument is a file holding a payload, such as one saved by 'listen --record' or
+the problem.json of an existing problem, or '-' to read a payload from stdin. The payload
+goes through the same steps as a problem received by 'listen'.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		imp, err := newImporter(cmd)
+		cobra.CheckErr(err)
+
+		failed := 0
+		for _, arg := range args {
+			var payload []byte
+			if arg == "-" {
+				payload, err = io.ReadAll(os.Stdin)
+			} else {
+				payload, err = os.ReadFile(arg)
+			}
+			if err != nil {
+				logger.Errorf("%s: %v", arg, err)
+				failed++
+				continue
+			}
+
+			res, err := imp.Import(payload)
+			if err != nil {
+				logger.Errorf("%s: %v", arg, err)
+				failed++
+				continue
+			}
+			markOpened(res.Problem)
+			fmt.Println(res.Dir)
+		}
+
+		if failed > 0 {
+			cobra.CheckErr(fmt.Errorf("%d of %d import(s) failed", failed, len(args)))
+		}
+	},
+}
+
+// newImporter builds an Importer from the configuration. A --reimport flag
+// on cmd, if set, takes precedence over the configured reimportPolicy.
+func newImporter(cmd *cobra.Command) (*importer.Importer, error) {
+	dm, err := newDirectoryManager()
+	if err != nil {
+		return nil, err
+	}
+
+	policyStr := viper.GetString("reimportPolicy")
+	if flag := cmd.Flags().Lookup("reimport"); flag != nil && flag.Changed {
+		policyStr = flag.Value.String()
+	}
+	policy, err := directorymanager.ParseReimportPolicy(policyStr)
+	if err != nil {
+		return nil, err
+	}
+
+	template := templatePath()
+	if template != "" {
+		// Fail before the first problem arrives rather than at its import.
+		f, err := os.Open(template)
+		if err != nil {
+			return nil, fmt.Errorf("templatePath: %w", err)
+		}
+		f.Close()
+	}
+
+	opts := importer.Options{
+		InputPrefix:  viper.GetString("testCaseInputPrefix"),
+		OutputPrefix: viper.GetString("testCaseOutputPrefix"),
+		Policy:       policy,
+		ProgramFile:  fmt.Sprintf("%s.%s", viper.GetString("programFile"), viper.GetString("language")),
+		TemplatePath: template,
+	}
+	if viper.GetBool("enrichMetadata") {
+		// The cached problemset only: an import never waits on the network.
+		opts.Lookup = cachedProblem
+	}
+	return importer.NewImporter(dm, opts, logger.Std()), nil
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().String("reimport", "", "what to do with existing samples of a re-imported problem: overwrite, keep or merge")
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/listing"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/upsolve"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the imported problems, filtered by contest, tag or rating",
+	Long: `Lists the imported problems, the most recent contest first. Tags and ratings are
+recorded in problem.json on import when a cached problemset is available (see
+'upsolve --refresh'); problems imported before that are looked up in the cache.
+
+  codeforces-cli list --tag dp --rating 1600-1900
+  codeforces-cli list --tag "two pointers" --tag greedy --rating -1400
+  codeforces-cli list --contest 1985
+
+Every --tag must match, ignoring case. A rating range leaves out unrated problems.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filter listing.Filter
+		filter.ContestID, _ = cmd.Flags().GetInt("contest")
+		filter.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		if s, _ := cmd.Flags().GetString("rating"); s != "" {
+			r, err := listing.ParseRatingRange(s)
+			if err != nil {
+				return err
+			}
+			filter.Rating = r
+		}
+
+		dm, err := newDirectoryManager()
+		if err != nil {
+			return err
+		}
+		entries, err := dm.ListProblems()
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			a, b := entries[i].Problem, entries[j].Problem
+			if a.ContestCode != b.ContestCode {
+				return a.ContestCode > b.ContestCode
+			}
+			return a.Index < b.Index
+		})
+
+		var cached map[string]*cfapi.Problem
+		root := viper.GetString("root")
+		found := 0
+		for _, entry := range entries {
+			p, meta := entry.Problem, entry.Metadata
+			tags, rating := meta.Tags, meta.Rating
+			if tags == nil && rating == 0 {
+				if cached == nil {
+					cached = cachedProblemset()
+				}
+				if c := cached[upsolve.ProblemID(p.ContestCode, p.Index)]; c != nil {
+					tags, rating = c.Tags, c.Rating
+				}
+			}
+			if !filter.Match(p, tags, rating) {
+				continue
+			}
+
+			if found == 0 {
+				fmt.Printf("%-10s%-8s%-40s%s\n", "Problem", "Rating", "Name", "Tags")
+			}
+			found++
+			ratingStr := "-"
+			if rating > 0 {
+				ratingStr = strconv.Itoa(rating)
+			}
+			tagStr := strings.Join(tags, ", ")
+			if tagStr == "" {
+				tagStr = "-"
+			}
+			dir, err := filepath.Rel(root, entry.Dir)
+			if err != nil {
+				dir = entry.Dir
+			}
+			name := fmt.Sprintf("%s (%s)", meta.Name, dir)
+			fmt.Printf("%-10s%-8s%-40s%s\n", upsolve.ProblemID(p.ContestCode, p.Index), ratingStr, name, tagStr)
+		}
+		if found == 0 {
+			fmt.Println("No problems match.")
+		}
+		return nil
+	},
+}
+
+// cachedProblemset indexes the cached problemset by problem id. It is empty,
+// not nil, when there is no cached copy.
+func cachedProblemset() map[string]*cfapi.Problem {
+	byID := map[string]*cfapi.Problem{}
+	api := newAPIClient()
+	api.SetOffline(true)
+	if set := problemset(api); set != nil {
+		for i, p := range set.Problems {
+			byID[upsolve.ProblemID(p.ContestID, p.Index)] = &set.Problems[i]
+		}
+	}
+	return byID
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().Int("contest", 0, "only list the problems of this contest")
+	listCmd.Flags().StringSlice("tag", nil, "only list problems with this tag (repeatable)")
+	listCmd.Flags().String("rating", "", "only list problems rated in this range, e.g. 1600-1900, 1600-, -1900 or 1600")
+}
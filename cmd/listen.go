@@ -2,18 +2,22 @@
 package cmd
 
 import (
-	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"os/exec"
+	"os"
 	"path/filepath"
-	"strings"
-	"text/template"
+	"strconv"
+	"sync"
 	"time"
 
-	"github.com/PriyanshuSharma23/codeforces-cli/internal/ccparser"
-	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/editor"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/importer"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -24,6 +28,16 @@ var listenCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		mux := http.NewServeMux()
 		port := viper.GetString("port")
+		recordDir, _ := cmd.Flags().GetString("record")
+
+		imp, err := newImporter(cmd)
+		cobra.CheckErr(err)
+
+		launcher, err := newEditorLauncher()
+		cobra.CheckErr(err)
+
+		// Editors still open, or waiting for the terminal, when the server closes.
+		var editors sync.WaitGroup
 
 		server := &http.Server{
 			Addr:    fmt.Sprintf(":%s", port),
@@ -31,112 +45,141 @@ var listenCmd = &cobra.Command{
 		}
 
 		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-			if r.Method != http.MethodPost {
-				http.Error(w, "Only POST supported", http.StatusMethodNotAllowed)
-				return
-			}
-
-			var ccproblem ccparser.CCProblem
-			if err := json.NewDecoder(r.Body).Decode(&ccproblem); err != nil {
-				http.Error(w, "Invalid JSON", http.StatusBadRequest)
-				return
-			}
-
-			parser := ccparser.NewParser(logger)
-
-			parsedProblem, err := parser.Parse(&ccproblem)
-			if err != nil {
-				http.Error(w, "Failed to parse problem", http.StatusInternalServerError)
-				return
-			}
-
-			dm := directorymanager.NewDirectoryManager(viper.GetString("root"), logger)
-			problemKey := directorymanager.Problem{
-				ContestCode: parsedProblem.ContestCode,
-				ProblemCode: parsedProblem.ProblemCode,
-			}
-
-			if _, err := dm.EnsureDir(problemKey); err != nil {
-				http.Error(w, "Could not prepare problem directory", http.StatusInternalServerError)
+			requestID := newRequestID()
+			started := time.Now()
+			w.Header().Set("X-Request-Id", requestID)
+			logger.Infof("[%s] %s %s from %s", requestID, r.Method, r.URL.Path, r.RemoteAddr)
+
+			res, ierr := handleImportRequest(r, requestID, imp, recordDir)
+			if ierr != nil {
+				logger.Errorf("[%s] stage %s: %v", requestID, ierr.Stage, ierr.Err)
+				writeJSON(w, ierr.Status, map[string]string{
+					"status":    "error",
+					"requestId": requestID,
+					"stage":     ierr.Stage,
+					"error":     ierr.Err.Error(),
+				})
 				return
 			}
+			logger.Infof("[%s] Imported %s in %s", requestID, res.Dir, time.Since(started).Round(time.Millisecond))
 
-			if err := dm.WriteTestCases(
-				problemKey,
-				parsedProblem.TestCases,
-				viper.GetString("testCaseInputPrefix"),
-				viper.GetString("testCaseOutputPrefix"),
-			); err != nil {
-				http.Error(w, "Error writing test cases", http.StatusInternalServerError)
-				return
-			}
-
-			templatePath := viper.GetString("templatePath")
-			var templateStr string
-			if templatePath != "" {
-				t, err := dm.LoadTemplate(templatePath)
-				cobra.CheckErr(err)
-				templateStr = t
-			}
-
-			progFile := fmt.Sprintf("%s.%s", viper.GetString("programFile"), viper.GetString("language"))
-			if err := dm.WriteProgramFile(problemKey, progFile, templateStr); err != nil {
-				http.Error(w, "Error writing program file", http.StatusInternalServerError)
-				return
-			}
-
-			if err := dm.WriteMetadata(problemKey, ccproblem); err != nil {
-				logger.Printf("Warning: could not write metadata: %v", err)
-			}
-
+			editors.Add(1)
 			go func() {
-				// 🔥 Open the editor using editorCommand
-				editorCmdTemplate := viper.GetString("editorCommand")
-				if editorCmdTemplate != "" {
-					editorTemplate, err := template.New("editor").Parse(editorCmdTemplate)
-					if err != nil {
-						logger.Printf("Invalid editorCommand template: %v", err)
-					} else {
-						var cmdBuf bytes.Buffer
-						err = editorTemplate.Execute(&cmdBuf, map[string]string{
-							"Path": filepath.Join(dm.FullProblemPath(problemKey), progFile),
-							"Dir":  filepath.Join(dm.FullProblemPath(problemKey)),
-						})
-						if err != nil {
-							logger.Printf("Failed to render editor command: %v", err)
-						} else {
-							editorArgs := strings.Fields(cmdBuf.String())
-							cmd := exec.Command(editorArgs[0], editorArgs[1:]...)
-							cmd.Dir = dm.FullProblemPath(problemKey)
-							err = cmd.Start()
-							if err != nil {
-								logger.Printf("Failed to launch editor: %v", err)
-							}
-						}
-					}
-
+				defer editors.Done()
+				vars := editor.Vars{
+					Path:  filepath.Join(res.Dir, res.ProgramFile),
+					Dir:   res.Dir,
+					Tests: testInputPaths(res.Dir),
+				}
+				if err := launcher.Open(vars); err != nil {
+					logger.Errorf("%v", err)
 				}
 
 				time.Sleep(2 * time.Second)
 				server.Close()
 			}()
 
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			_ = json.NewEncoder(w).Encode(map[string]string{
+			writeJSON(w, http.StatusOK, map[string]string{
 				"status":      "success",
-				"problemPath": problemKey.RelativeDir(),
-				"programFile": progFile,
+				"requestId":   requestID,
+				"problemPath": res.RelativeDir,
+				"programFile": res.ProgramFile,
 			})
 		})
 
-		fmt.Printf("🟢 Listening on http://localhost:%s...\n", port)
+		logger.Infof("🟢 Listening on http://localhost:%s...", port)
 		if err := server.ListenAndServe(); err != http.ErrServerClosed {
-			fmt.Printf("❌ Server error: %v\n", err)
+			logger.Errorf("Server error: %v", err)
 		}
+		editors.Wait()
 	},
 }
 
+// importError is a failed import together with the stage it failed at and
+// the HTTP status reported for it.
+type importError struct {
+	Stage  string
+	Status int
+	Err    error
+}
+
+// handleImportRequest runs a Competitive Companion request through the import
+// pipeline. Failures are returned rather than exiting, so one bad request
+// never takes the listener down.
+func handleImportRequest(r *http.Request, requestID string, imp *importer.Importer, recordDir string) (*importer.Result, *importError) {
+	if r.Method != http.MethodPost {
+		return nil, &importError{"request", http.StatusMethodNotAllowed, fmt.Errorf("only POST supported, got %s", r.Method)}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, &importError{"request", http.StatusBadRequest, fmt.Errorf("reading body: %w", err)}
+	}
+	logger.Debugf("[%s] Payload: %s", requestID, body)
+	if recordDir != "" {
+		if err := recordPayload(recordDir, requestID, body); err != nil {
+			logger.Warnf("[%s] could not record payload: %v", requestID, err)
+		}
+	}
+
+	res, err := imp.Import(body)
+	if err != nil {
+		ierr := &importError{"import", http.StatusInternalServerError, err}
+		var stageErr *importer.Error
+		if errors.As(err, &stageErr) {
+			ierr.Stage, ierr.Err = stageErr.Stage, stageErr.Err
+			switch stageErr.Stage {
+			case importer.StageDecode:
+				ierr.Status = http.StatusBadRequest
+			case importer.StageParse:
+				ierr.Status = http.StatusUnprocessableEntity
+			}
+		}
+		return nil, ierr
+	}
+	markOpened(res.Problem)
+	return res, nil
+}
+
+// recordPayload saves a raw request body so it can be replayed with 'import'.
+func recordPayload(dir, requestID string, body []byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.json", time.Now().Format("20060102-150405"), requestID)
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return err
+	}
+	logger.Infof("[%s] Recorded payload to %s", requestID, path)
+	return nil
+}
+
+// testInputPaths lists the test input files of the problem in dir.
+func testInputPaths(dir string) []string {
+	pattern := filepath.Join(dir, execution.TestsDir, "*-"+viper.GetString("testCaseInputPrefix")+"*")
+	paths, _ := filepath.Glob(pattern)
+	return paths
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// newRequestID returns a short random identifier used to correlate log lines.
+func newRequestID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
 func init() {
 	rootCmd.AddCommand(listenCmd)
+
+	listenCmd.Flags().String("reimport", "", "what to do with existing samples of a re-imported problem: overwrite, keep or merge")
+	listenCmd.Flags().String("record", "", "directory to save every received payload to, for replay with 'import'")
 }
@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// migrateLayoutCmd represents the migrate-layout command
+var migrateLayoutCmd = &cobra.Command{
+	Use:   "migrate-layout",
+	Short: "Move existing problems to a new directory layout",
+	Long: `Moves every problem stored below 'root' to the location given by a layout template.
+
+Problems are discovered through their problem.json, so they are found wherever an
+earlier layout placed them. The target layout defaults to the configured 'layout';
+use --to to migrate to a different one, which is then written to the config file as
+'layout' once every problem has moved.
+
+Available template fields: {{.Judge}}, {{.Contest}}, {{.Index}}, {{.Code}} and {{.Year}}.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		layout, _ := cmd.Flags().GetString("to")
+		if layout == "" {
+			layout = viper.GetString("layout")
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		dm, err := newDirectoryManager()
+		cobra.CheckErr(err)
+
+		moves, err := dm.MigrateLayout(layout, dryRun)
+		cobra.CheckErr(err)
+
+		failed := 0
+		for _, move := range moves {
+			if move.Err != nil {
+				logger.Errorf("%s: %v", move.From, move.Err)
+				failed++
+				continue
+			}
+			fmt.Printf("%s -> %s\n", move.From, move.To)
+		}
+
+		if dryRun {
+			fmt.Printf("%d problem(s) would be moved\n", len(moves)-failed)
+		} else {
+			fmt.Printf("Moved %d problem(s)\n", len(moves)-failed)
+		}
+		if failed > 0 {
+			cobra.CheckErr(fmt.Errorf("%d problem(s) could not be moved", failed))
+		}
+		if dryRun || layout == viper.GetString("layout") {
+			return
+		}
+		path, err := writeConfigKey("layout", layout)
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("saving the layout failed, run 'codeforces-cli config set layout %q': %w", layout, err))
+		}
+		fmt.Printf("layout = %s (%s)\n", layout, path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateLayoutCmd)
+
+	migrateLayoutCmd.Flags().String("to", "", "layout template to migrate to (default is the configured layout)")
+	migrateLayoutCmd.Flags().Bool("dry-run", false, "only print the moves that would be made")
+}
@@ -0,0 +1,260 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/minimize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// minimizeCmd represents the minimize command
+var minimizeCmd = &cobra.Command{
+	Use:   "minimize <test>",
+	Short: "Shrink a failing test input with delta debugging",
+	Long: `Shrinks the input of a failing test, given the way 'execute' names it (e.g. 3 for
+input3, or custom-2 for tests/custom-input2), by removing lines (or, with --by tokens,
+tokens) for as long as the program still disagrees with a brute-force solution.
+
+The brute-force solution is 'bruteFile' (default brute) with the configured language's
+extension, built and run with 'bruteBuildCommand' and 'bruteExecuteCommand'; each of them
+that is empty falls back to 'buildCommand' or 'executeCommand'. Its output is taken as the
+expected one. A candidate input only counts when the brute-force solution runs on it
+without errors, the program fails on it with the same verdict as on the original input
+and, if set, 'validatorCommand' (or --validator) accepts it by exiting with status 0.
+
+The minimal input is saved as the next custom test, with the brute-force output as its
+expected output.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		byStr, _ := cmd.Flags().GetString("by")
+		by, err := minimize.ParseGranularity(byStr)
+		if err != nil {
+			return err
+		}
+
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		dm, err := newDirectoryManager()
+		if err != nil {
+			return err
+		}
+		p, err := dm.ProblemKeyForDir(dir)
+		if err != nil {
+			return fmt.Errorf("%s is not a problem directory: %w", dir, err)
+		}
+
+		inputPath, err := testInputPath(dir, args[0])
+		if err != nil {
+			return err
+		}
+		input, err := os.ReadFile(inputPath)
+		if err != nil {
+			return err
+		}
+
+		language := viper.GetString("language")
+		programPath := filepath.Join(dir, fmt.Sprintf("%s.%s", viper.GetString("programFile"), language))
+		brutePath := filepath.Join(dir, fmt.Sprintf("%s.%s", viper.GetString("bruteFile"), language))
+		if _, err := os.Stat(brutePath); err != nil {
+			return fmt.Errorf("no brute-force solution: %w", err)
+		}
+
+		program, programExec, err := newProgramEngine(dir, programPath, "buildCommand", "executeCommand")
+		if err != nil {
+			return err
+		}
+		checker, err := execution.ParseChecker(viper.GetString("checker"))
+		if err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		program.SetChecker(checker)
+		program.SetTimeLimit(time.Duration(viper.GetInt("timeLimit")) * time.Millisecond)
+
+		bruteBuild, bruteExec := "bruteBuildCommand", "bruteExecuteCommand"
+		if viper.GetString(bruteBuild) == "" {
+			bruteBuild = "buildCommand"
+		}
+		if viper.GetString(bruteExec) == "" {
+			bruteExec = "executeCommand"
+		}
+		brute, renderedBruteExec, err := newProgramEngine(dir, brutePath, bruteBuild, bruteExec)
+		if err != nil {
+			return err
+		}
+		if renderedBruteExec == programExec {
+			// A build with a fixed output, e.g. -o main, would replace the
+			// program with the brute-force solution.
+			return withExitCode(exitConfigError, fmt.Errorf("the program and the brute-force solution would both run %q; set bruteBuildCommand and bruteExecuteCommand", programExec))
+		}
+
+		validatorCommand := viper.GetString("validatorCommand")
+		if flag := cmd.Flags().Lookup("validator"); flag.Changed {
+			validatorCommand = flag.Value.String()
+		}
+		var validator *execution.Engine
+		if validatorCommand != "" {
+			rendered, err := renderCommand("validator", validatorCommand, map[string]string{"Dir": dir})
+			if err != nil {
+				return withExitCode(exitConfigError, err)
+			}
+			validator = execution.NewEngine(viper.GetString("root"), dir, "", rendered, "", "", log.New(io.Discard, "", 0))
+			validator.SetStderr(io.Discard)
+		}
+
+		var buildErr *execution.BuildError
+		for _, e := range []*execution.Engine{program, brute} {
+			if err := e.Build(); errors.As(err, &buildErr) {
+				cmd.SilenceErrors = true
+				return withExitCode(exitCompileError, nil)
+			} else if err != nil {
+				return err
+			}
+		}
+
+		m := &minimizer{program: program, brute: brute, validator: validator}
+		failed, err := m.check(string(input))
+		if err != nil {
+			return err
+		}
+		if !failed {
+			return fmt.Errorf("the program agrees with the brute-force solution on %s; nothing to minimize", filepath.Base(inputPath))
+		}
+		logger.Infof("The program fails with %s on %d line(s) of input; minimizing by %s", m.verdict, lineCount(string(input)), by)
+
+		dd := minimize.New(m.check)
+		dd.SetProgress(func(in string) {
+			logger.Infof("Shrunk to %d line(s), %d byte(s)", lineCount(in), len(in))
+		})
+		minimal, err := dd.Minimize(string(input), by)
+		if err != nil {
+			return err
+		}
+		expected, err := brute.Run(execution.TestCase{Input: minimal})
+		if err != nil {
+			return err
+		}
+
+		num, err := dm.AddCustomTest(p, execution.TestCase{Input: minimal, Output: expected.ProgramOutput},
+			viper.GetString("testCaseInputPrefix"), viper.GetString("testCaseOutputPrefix"))
+		if err != nil {
+			return err
+		}
+		fmt.Print(minimal)
+		fmt.Printf("Saved as test custom-%d (%d of %d line(s), %d run(s))\n", num, lineCount(minimal), lineCount(string(input)), dd.Tests)
+		return nil
+	},
+}
+
+// minimizer decides whether an input still shows the failure being
+// minimized.
+type minimizer struct {
+	program, brute, validator *execution.Engine
+	verdict                   execution.Verdict // of the program on the original input
+}
+
+// check runs the validator, brute-force solution and program on input. The
+// first failing input sets the verdict later inputs must fail with.
+func (m *minimizer) check(input string) (bool, error) {
+	if m.validator != nil {
+		r, err := m.validator.Run(execution.TestCase{Input: input})
+		if err != nil {
+			return false, fmt.Errorf("running the validator: %w", err)
+		}
+		if !ranCleanly(r) {
+			return false, nil
+		}
+	}
+
+	expected, err := m.brute.Run(execution.TestCase{Input: input})
+	if err != nil {
+		return false, fmt.Errorf("running the brute-force solution: %w", err)
+	}
+	if !ranCleanly(expected) {
+		return false, nil
+	}
+
+	r, err := m.program.Run(execution.TestCase{Input: input, Output: expected.ProgramOutput})
+	if err != nil {
+		return false, err
+	}
+	if r.Ok {
+		return false, nil
+	}
+	if m.verdict == "" {
+		m.verdict = r.Verdict
+	}
+	return r.Verdict == m.verdict, nil
+}
+
+// ranCleanly reports whether a program exited with status 0 in time,
+// whatever it printed.
+func ranCleanly(r execution.Result) bool {
+	return r.Verdict != execution.VerdictRuntimeError && r.Verdict != execution.VerdictTimeLimit
+}
+
+// newProgramEngine prepares an engine running the program at path with the
+// build and execute commands configured under the given keys, and returns
+// the rendered execute command. What the program writes to stderr and the
+// warnings of failing runs are dropped, as most candidate inputs fail.
+func newProgramEngine(dir, path, buildKey, execKey string) (*execution.Engine, string, error) {
+	variables := map[string]string{
+		"Path": path,
+		"Dir":  dir,
+	}
+	renderedBuild, err := renderCommand("build", viper.GetString(buildKey), variables)
+	if err != nil {
+		return nil, "", withExitCode(exitConfigError, err)
+	}
+	renderedExec, err := renderCommand("exec", viper.GetString(execKey), variables)
+	if err != nil {
+		return nil, "", withExitCode(exitConfigError, err)
+	}
+	logger.Debugf("Running %s with: %s", filepath.Base(path), renderedExec)
+
+	e := execution.NewEngine(viper.GetString("root"), dir, renderedBuild, renderedExec, "", "", log.New(io.Discard, "", 0))
+	e.SetStderr(io.Discard)
+	return e, renderedExec, nil
+}
+
+// testInputPath finds the input file of a test named the way 'execute'
+// names it: "3" for input3, "custom-2" for tests/custom-input2.
+func testInputPath(dir, name string) (string, error) {
+	prefix := viper.GetString("testCaseInputPrefix")
+	group, numStr, grouped := strings.Cut(name, "-")
+	if !grouped {
+		numStr = name
+	}
+	if _, err := strconv.Atoi(numStr); err != nil {
+		return "", fmt.Errorf("invalid test %q (expected e.g. 3 or custom-2)", name)
+	}
+	if grouped {
+		return filepath.Join(dir, execution.TestsDir, group+"-"+prefix+numStr), nil
+	}
+	return filepath.Join(dir, prefix+numStr), nil
+}
+
+func lineCount(s string) int {
+	return strings.Count(strings.TrimRight(s, "\n"), "\n") + 1
+}
+
+func init() {
+	rootCmd.AddCommand(minimizeCmd)
+
+	minimizeCmd.Flags().String("by", string(minimize.ByLines), "what to remove from the input: lines or tokens")
+	minimizeCmd.Flags().String("validator", "", "command checking that an input is valid by exiting with status 0 (default is validatorCommand)")
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/editor"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/notes"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// noteCmd represents the note command
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Open the notes of the current problem, or search all notes",
+	Long: `Opens NOTES.md in the current problem directory with 'editorCommand', creating it first
+with the problem's name, URL, limits and its rating and tags, as recorded on import or
+found in a cached problemset (see 'upsolve --refresh').
+
+With --search, prints every line of the notes below 'root' containing the text,
+ignoring case:
+
+  codeforces-cli note --search "segment tree"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if query, _ := cmd.Flags().GetString("search"); query != "" {
+			return searchNotes(query)
+		}
+
+		dm, err := newDirectoryManager()
+		if err != nil {
+			return err
+		}
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		p, err := dm.ProblemKeyForDir(cwd)
+		if err != nil {
+			return fmt.Errorf("%s is not a problem directory: %w", cwd, err)
+		}
+		meta, err := dm.ReadMetadata(p)
+		if err != nil {
+			return err
+		}
+
+		dir := dm.FullProblemPath(p)
+		path := filepath.Join(dir, notes.FileName)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			info := notes.Info{
+				Name:          meta.Name,
+				URL:           meta.URL,
+				TimeLimitMs:   meta.TimeLimit,
+				MemoryLimitMB: meta.MemoryLimit,
+				Tags:          meta.Tags,
+				Rating:        meta.Rating,
+			}
+			if info.Tags == nil && info.Rating == 0 {
+				if cached := cachedProblem(p.ContestCode, p.Index); cached != nil {
+					info.Tags, info.Rating = cached.Tags, cached.Rating
+				}
+			}
+			if err := os.WriteFile(path, []byte(notes.Template(info)), 0o644); err != nil {
+				return err
+			}
+			logger.Infof("Created %s", path)
+		}
+
+		launcher, err := newEditorLauncher()
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return launcher.Open(editor.Vars{Path: path, Dir: dir, Tests: testInputPaths(dir)})
+	},
+}
+
+func searchNotes(query string) error {
+	root := viper.GetString("root")
+	matches, err := notes.Search(root, query)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no notes mention %q", query)
+	}
+	for _, m := range matches {
+		path, err := filepath.Rel(root, m.Path)
+		if err != nil {
+			path = m.Path
+		}
+		fmt.Printf("%s:%d: %s\n", color.CyanString(path), m.Line, m.Text)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+
+	noteCmd.Flags().StringP("search", "s", "", "search the notes of all problems for this text")
+}
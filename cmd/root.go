@@ -4,18 +4,33 @@ Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/doctor"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/editor"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/logging"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
 
-var logger = log.New(os.Stdout, "", log.LstdFlags)
+// skipConfigValidation is the annotation marking commands that must run even
+// with an invalid configuration, such as the ones used to fix it.
+const skipConfigValidation = "skipConfigValidation"
+
+// logger writes diagnostics to stderr, keeping stdout for command results.
+var logger = logging.New(os.Stderr, logging.LevelInfo)
+
+// logFile is the file opened for --log-file, closed when the command ends.
+var logFile *os.File
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -43,15 +58,69 @@ your development environment.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		for c := cmd; c != nil; c = c.Parent() {
+			if c.Annotations[skipConfigValidation] != "" || c.Name() == "help" || c.Name() == "completion" {
+				return nil
+			}
+		}
+		cmd.SilenceUsage = true
+		if err := validateConfig(); err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	if logFile != nil {
+		logFile.Close()
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.code)
+	}
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitFailure)
+	}
+}
+
+// Exit statuses, documented in the README so scripts and git hooks can rely
+// on them. Errors without a specific status, such as usage errors or files
+// that can't be read, exit with exitFailure.
+const (
+	exitWrongAnswer  = 1
+	exitCompileError = 2
+	exitRuntimeError = 3 // runtime error or time limit exceeded
+	exitConfigError  = 4
+	exitNoTests      = 5
+	exitFailure      = 6
+)
+
+// exitError ends the program with a specific exit status. Without an
+// underlying error it prints nothing, for commands whose output already
+// explains the outcome.
+type exitError struct {
+	code int
+	err  error
+}
+
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
 	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
 }
 
 func init() {
@@ -62,6 +131,10 @@ func init() {
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.codeforces-cli.yaml)")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "log debug messages")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "log errors only")
+	rootCmd.PersistentFlags().String("log-file", "", "also write every log message to this file")
+	bindFlag("logFile", rootCmd.PersistentFlags().Lookup("log-file"))
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -69,30 +142,32 @@ func init() {
 }
 
 func initConfig() {
+	setupLogging()
+
 	if cfgFile != "" {
 		// Use config file from the --config flag
 		viper.SetConfigFile(cfgFile)
-		fmt.Println("[config] Using config file from flag:", cfgFile)
+		logger.Debugf("[config] Using config file from flag: %s", cfgFile)
 	} else {
 		// Get OS-specific config dir
 		configDir, err := os.UserConfigDir()
 		cobra.CheckErr(err)
 
-		fmt.Println("[config] Config dir:", configDir)
+		logger.Debugf("[config] Config dir: %s", configDir)
 
 		// Default: ~/.config/codeforces-cli/config.yaml (or %AppData%\codeforces-cli\config.yaml)
 		defaultPath := filepath.Join(configDir, "codeforces-cli", "config.yaml")
 
 		if _, err := os.Stat(defaultPath); err == nil {
 			viper.SetConfigFile(defaultPath)
-			fmt.Println("[config] Found primary config at:", defaultPath)
+			logger.Debugf("[config] Found primary config at: %s", defaultPath)
 		} else {
 			// Fallback: ~/codeforces-cli.yaml
 			home, err := os.UserHomeDir()
 			cobra.CheckErr(err)
 
 			fallback := filepath.Join(home, "codeforces-cli.yaml")
-			fmt.Println("[config] Trying fallback config at:", fallback)
+			logger.Debugf("[config] Trying fallback config at: %s", fallback)
 
 			viper.SetConfigFile(fallback)
 		}
@@ -104,9 +179,11 @@ func initConfig() {
 
 	// Try to read the selected config
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "✅ Using config file:", viper.ConfigFileUsed())
+		logger.Debugf("[config] Using config file: %s", viper.ConfigFileUsed())
+	} else if errors.Is(err, fs.ErrNotExist) {
+		logger.Debugf("[config] No config file found, using defaults: %v", err)
 	} else {
-		fmt.Fprintln(os.Stderr, "⚠️ No config file found or failed to read:", err)
+		logger.Warnf("[config] Failed to read config file: %v", err)
 	}
 
 	// Set default values
@@ -125,5 +202,213 @@ func initConfig() {
 	viper.SetDefault("testCaseOutputPrefix", "output")
 	viper.SetDefault("port", 10045)
 	viper.SetDefault("editorCommand", "nvim {{.Path}}")
+	viper.SetDefault("editorMode", string(editor.ModeForeground))
+	viper.SetDefault("terminalCommand", "x-terminal-emulator -e {{.Command}}")
+	viper.SetDefault("editorServer", "")
+	viper.SetDefault("remoteCommand", "")
+	viper.SetDefault("checker", "exact")
+	viper.SetDefault("timeLimit", 0)
+	viper.SetDefault("multitestInputLines", 0)
+	viper.SetDefault("multitestOutputLines", 0)
+	viper.SetDefault("bruteFile", "brute")
+	viper.SetDefault("bruteBuildCommand", "")
+	viper.SetDefault("bruteExecuteCommand", "")
+	viper.SetDefault("validatorCommand", "")
+	viper.SetDefault("includePaths", []string{})
+	viper.SetDefault("bundleStripComments", false)
+	viper.SetDefault("libraryDir", "")
+	viper.SetDefault("lintRules", []string{})
+	viper.SetDefault("lintAfterExecute", false)
+	viper.SetDefault("baseURL", "https://codeforces.com")
+	viper.SetDefault("apiURL", "")
+	viper.SetDefault("handle", "")
+	viper.SetDefault("apiKey", "")
+	viper.SetDefault("apiSecret", "")
+	viper.SetDefault("submitLanguageIds", map[string]string{})
+	viper.SetDefault("logLevel", "info")
 	viper.SetDefault("templatePath", defaultTemplatePath)
+	viper.SetDefault("layout", directorymanager.DefaultLayout)
+	viper.SetDefault("reimportPolicy", string(directorymanager.ReimportOverwrite))
+	viper.SetDefault("enrichMetadata", true)
+
+	mergeDirectoryOverrides()
+
+	// The config file may set logLevel or logFile, so apply them again.
+	setupLogging()
+}
+
+// setupLogging applies -v/-q, logLevel and --log-file/logFile to the logger.
+func setupLogging() {
+	verbose, _ := rootCmd.PersistentFlags().GetBool("verbose")
+	quiet, _ := rootCmd.PersistentFlags().GetBool("quiet")
+
+	level, err := logging.ParseLevel(viper.GetString("logLevel"))
+	if err != nil {
+		level = logging.LevelInfo
+	}
+	switch {
+	case verbose:
+		level = logging.LevelDebug
+	case quiet:
+		level = logging.LevelError
+	}
+	logger.SetLevel(level)
+
+	path := viper.GetString("logFile")
+	if path == "" || logFile != nil && logFile.Name() == path {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		logger.Warnf("could not open log file: %v", err)
+		return
+	}
+	if logFile != nil {
+		logFile.Close()
+	}
+	logFile = f
+	logger.SetFile(f)
+}
+
+// overrideOrigins maps the keys set by per-directory override files to the
+// file that set them last.
+var overrideOrigins = map[string]string{}
+
+// mergeDirectoryOverrides merges the .cfcli.yaml files found between root and
+// the working directory over the configuration, the deepest file winning.
+// root and layout decide where the override files are, so they cannot be
+// overridden themselves.
+func mergeDirectoryOverrides() {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+
+	dm := directorymanager.NewDirectoryManager(viper.GetString("root"), logger.Std())
+	for _, path := range dm.OverrideFiles(cwd) {
+		v := viper.New()
+		v.SetConfigFile(path)
+		v.SetConfigType("yaml")
+		if err := v.ReadInConfig(); err != nil {
+			logger.Warnf("Ignoring unreadable override file: %v", err)
+			continue
+		}
+
+		settings := v.AllSettings()
+		for _, key := range []string{"root", "layout"} {
+			if _, ok := settings[key]; ok {
+				logger.Warnf("Ignoring %s in %s: it can only be set globally", key, path)
+				delete(settings, key)
+			}
+		}
+
+		if err := viper.MergeConfigMap(settings); err != nil {
+			logger.Warnf("Ignoring override file %s: %v", path, err)
+			continue
+		}
+		for key := range settings {
+			overrideOrigins[key] = path
+		}
+		logger.Debugf("[config] Using override file: %s", path)
+	}
+}
+
+// newDirectoryManager returns a DirectoryManager for the configured root and layout.
+func newDirectoryManager() (*directorymanager.DirectoryManager, error) {
+	dm := directorymanager.NewDirectoryManager(viper.GetString("root"), logger.Std())
+	if err := dm.SetLayout(viper.GetString("layout")); err != nil {
+		return nil, err
+	}
+	return dm, nil
+}
+
+// appConfigDir is the directory holding the configuration and the state
+// kept between runs, such as the login session.
+func appConfigDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "codeforces-cli"), nil
+}
+
+// newAPIClient returns a Codeforces API client for the configured baseURL,
+// caching responses in the user's cache directory.
+func newAPIClient() *cfapi.Client {
+	apiURL := viper.GetString("apiURL")
+	if apiURL == "" {
+		apiURL = strings.TrimRight(viper.GetString("baseURL"), "/") + "/api"
+	}
+	client := cfapi.NewClient(apiURL, logger.Std())
+	client.SetCredentials(viper.GetString("apiKey"), viper.GetString("apiSecret"))
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		client.SetCacheDir(filepath.Join(cacheDir, "codeforces-cli", "api"))
+	}
+	return client
+}
+
+// newEditorLauncher returns a Launcher for the configured editor settings.
+func newEditorLauncher() (*editor.Launcher, error) {
+	return editor.NewLauncher(editor.Config{
+		Mode:            editor.Mode(viper.GetString("editorMode")),
+		Command:         viper.GetString("editorCommand"),
+		TerminalCommand: viper.GetString("terminalCommand"),
+		Server:          viper.GetString("editorServer"),
+		RemoteCommand:   viper.GetString("remoteCommand"),
+	}, logger.Std())
+}
+
+// templatePath returns the template new program files start from. The
+// default template is optional: when it doesn't exist, program files start
+// empty, while a missing template the user set is reported at import.
+func templatePath() string {
+	path := viper.GetString("templatePath")
+	if configOrigin("templatePath") == "default" {
+		if _, err := os.Stat(path); err != nil {
+			return ""
+		}
+	}
+	return path
+}
+
+// configSettings collects the configuration values checked by the doctor package.
+func configSettings() doctor.Settings {
+	return doctor.Settings{
+		Root:            viper.GetString("root"),
+		Language:        viper.GetString("language"),
+		ProgramFile:     viper.GetString("programFile"),
+		BuildCommand:    viper.GetString("buildCommand"),
+		ExecuteCommand:  viper.GetString("executeCommand"),
+		InputPrefix:     viper.GetString("testCaseInputPrefix"),
+		OutputPrefix:    viper.GetString("testCaseOutputPrefix"),
+		Port:            viper.GetString("port"),
+		Layout:          viper.GetString("layout"),
+		ReimportPolicy:  viper.GetString("reimportPolicy"),
+		TemplatePath:    templatePath(),
+		EditorMode:      viper.GetString("editorMode"),
+		EditorCommand:   viper.GetString("editorCommand"),
+		TerminalCommand: viper.GetString("terminalCommand"),
+		EditorServer:    viper.GetString("editorServer"),
+		RemoteCommand:   viper.GetString("remoteCommand"),
+		Checker:         viper.GetString("checker"),
+		TimeLimit:       viper.GetInt("timeLimit"),
+		IncludePaths:    viper.GetStringSlice("includePaths"),
+		LibraryDir:      viper.GetString("libraryDir"),
+		LintRules:       viper.GetStringSlice("lintRules"),
+	}
+}
+
+// validateConfig fails when a configuration value is invalid, before a
+// command gets to use it.
+func validateConfig() error {
+	var problems []string
+	for _, c := range doctor.Validate(configSettings()) {
+		if c.Status == doctor.Fail {
+			problems = append(problems, fmt.Sprintf("%s: %s", c.Name, c.Message))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration (run 'codeforces-cli doctor' for details):\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
 }
@@ -0,0 +1,251 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/submit"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// submitCmd represents the submit command
+var submitCmd = &cobra.Command{
+	Use:   "submit [file]",
+	Short: "Submit the solution of the current problem to Codeforces",
+	Long: `Submits the program file, 'main.<language>' in the current directory unless a file is
+given, to the problem recorded in the problem.json of the current problem directory,
+and waits for the verdict, showing the progress of the testing. The verdict is
+recorded in problem.json.
+
+The compiler is chosen from the file extension; override the Codeforces language id of
+an extension with 'submitLanguageIds', e.g. {py: "70"} for PyPy 3.
+
+The command logs in as 'handle' when needed. The password is read from the
+CFCLI_PASSWORD environment variable or asked for, never from the config file. The session
+is kept in the config directory, so later submissions don't need it.
+
+Use --bundle to submit the bundle of the program (see 'bundle') after checking it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		programPath := fmt.Sprintf("%s.%s", viper.GetString("programFile"), viper.GetString("language"))
+		if len(args) == 1 {
+			programPath = args[0]
+		}
+		programPath, err := filepath.Abs(programPath)
+		if err != nil {
+			return err
+		}
+		language := strings.TrimPrefix(filepath.Ext(programPath), ".")
+
+		dm, err := newDirectoryManager()
+		if err != nil {
+			return err
+		}
+		p, err := dm.ProblemKeyForDir(filepath.Dir(programPath))
+		if err != nil {
+			return err
+		}
+		meta, err := dm.ReadMetadata(p)
+		if err != nil {
+			return fmt.Errorf("reading problem.json: %w", err)
+		}
+		target, err := submit.ParseTarget(meta.URL)
+		if err != nil {
+			return err
+		}
+
+		sourcePath := programPath
+		if doBundle, _ := cmd.Flags().GetBool("bundle"); doBundle {
+			sourcePath, err = bundleProgram(programPath, language, viper.GetBool("bundleStripComments"))
+			if err != nil {
+				return err
+			}
+			if err := checkBundle(cmd, filepath.Dir(programPath), sourcePath, language == "py"); err != nil {
+				return err
+			}
+		}
+		source, err := os.ReadFile(sourcePath)
+		if err != nil {
+			return err
+		}
+
+		submitter, err := newSubmitter()
+		if err != nil {
+			return err
+		}
+		id, err := submitter.Submit(submit.Request{Target: target, Language: language, Source: source})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Submitted %s: %s\n", filepath.Base(sourcePath), submissionURL(target, id))
+
+		if noWait, _ := cmd.Flags().GetBool("no-wait"); noWait {
+			return nil
+		}
+
+		// Progress goes to stderr, keeping stdout for the results.
+		status := newStatusLine(os.Stderr)
+		poller := submit.NewPoller(newAPIClient(), 5*time.Second, 10*time.Minute, logger.Std())
+		poller.SetProgress(func(sub *cfapi.Submission) {
+			if !submit.Final(sub.Verdict) {
+				status.Update(progressText(sub))
+			}
+		})
+		sub, err := poller.Wait(viper.GetString("handle"), id)
+		status.Clear()
+		if err != nil {
+			return err
+		}
+
+		err = dm.UpdateMetadata(p, func(meta *directorymanager.Metadata) {
+			meta.Submissions = append(meta.Submissions, directorymanager.SubmissionRecord{
+				ID:          sub.ID,
+				SubmittedAt: time.Unix(sub.CreationTimeSeconds, 0),
+				Language:    language,
+				Verdict:     sub.Verdict,
+				PassedTests: sub.PassedTestCount,
+				TimeMs:      sub.TimeConsumedMillis,
+				MemoryBytes: sub.MemoryConsumedBytes,
+			})
+		})
+		if err != nil {
+			logger.Warnf("Failed to record the verdict in problem.json: %v", err)
+		}
+
+		usage := fmt.Sprintf("%d ms, %d KB", sub.TimeConsumedMillis, sub.MemoryConsumedBytes/1024)
+		switch sub.Verdict {
+		case "OK":
+			color.Green("Verdict: Accepted (%s)", usage)
+			return nil
+		case "COMPILATION_ERROR":
+			color.Red("Verdict: Compilation error")
+			cmd.SilenceErrors = true
+			return withExitCode(exitCompileError, nil)
+		default:
+			color.Red("Verdict: %s on test %d (%s)", verdictName(sub.Verdict), sub.PassedTestCount+1, usage)
+			cmd.SilenceErrors = true
+			return withExitCode(1, nil)
+		}
+	},
+}
+
+// progressText describes a submission that is still being judged.
+func progressText(sub *cfapi.Submission) string {
+	if sub.Verdict == "" {
+		return "In queue…"
+	}
+	return fmt.Sprintf("Running on test %d…", sub.PassedTestCount+1)
+}
+
+// verdictName turns an API verdict such as WRONG_ANSWER into "Wrong answer".
+func verdictName(verdict string) string {
+	name := strings.ToLower(strings.ReplaceAll(verdict, "_", " "))
+	if name == "" {
+		return verdict
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// statusLine shows a line that is replaced by every update on a terminal.
+// Elsewhere each new text is printed on a line of its own.
+type statusLine struct {
+	w        *os.File
+	terminal bool
+	last     string
+}
+
+func newStatusLine(w *os.File) *statusLine {
+	info, err := w.Stat()
+	return &statusLine{w: w, terminal: err == nil && info.Mode()&os.ModeCharDevice != 0}
+}
+
+func (s *statusLine) Update(text string) {
+	if text == s.last {
+		return
+	}
+	s.last = text
+	if s.terminal {
+		fmt.Fprintf(s.w, "\r\033[K%s", text)
+	} else {
+		fmt.Fprintln(s.w, text)
+	}
+}
+
+// Clear removes the line so the next output starts at the beginning of it.
+func (s *statusLine) Clear() {
+	if s.terminal && s.last != "" {
+		fmt.Fprint(s.w, "\r\033[K")
+	}
+	s.last = ""
+}
+
+// newSubmitter returns the submitter for the configured account.
+func newSubmitter() (submit.Submitter, error) {
+	dir, err := appConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	handle := viper.GetString("handle")
+	if handle == "" {
+		return nil, withExitCode(exitConfigError, errors.New("set 'handle' to your Codeforces handle to submit"))
+	}
+
+	return submit.NewWebSubmitter(submit.WebOptions{
+		BaseURL:     viper.GetString("baseURL"),
+		Handle:      handle,
+		Password:    readPassword,
+		CookieFile:  filepath.Join(dir, "cookies.json"),
+		LanguageIDs: viper.GetStringMapString("submitLanguageIds"),
+	}, logger.Std())
+}
+
+// readPassword takes the password from CFCLI_PASSWORD or asks for it
+// without echoing it. It is never read from the config file.
+func readPassword() (string, error) {
+	if password := os.Getenv("CFCLI_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Codeforces password for %s: ", viper.GetString("handle"))
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("reading password: %w", err)
+		}
+		return string(password), nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func submissionURL(target submit.Target, id int64) string {
+	base := strings.TrimRight(viper.GetString("baseURL"), "/")
+	if target.Kind == "problemset" {
+		return fmt.Sprintf("%s/problemset/submission/%d/%d", base, target.ContestID, id)
+	}
+	return fmt.Sprintf("%s/%s/%d/submission/%d", base, target.Kind, target.ContestID, id)
+}
+
+func init() {
+	rootCmd.AddCommand(submitCmd)
+
+	submitCmd.Flags().Bool("bundle", false, "bundle the program and check the bundle before submitting it")
+	submitCmd.Flags().Bool("no-wait", false, "don't wait for the verdict")
+}
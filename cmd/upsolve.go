@@ -0,0 +1,168 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/upsolve"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// upsolveCmd represents the upsolve command
+var upsolveCmd = &cobra.Command{
+	Use:   "upsolve",
+	Short: "List the imported problems left to upsolve",
+	Long: `Lists the imported problems that never passed all their tests (and were never
+accepted), as well as the problems marked 'todo', the most recent contest first.
+Problems marked 'done' or 'skip' are left out:
+
+  codeforces-cli upsolve mark 1985C todo
+  codeforces-cli upsolve mark . skip     # the problem in the current directory
+
+Contest dates and problem ratings come from cached copies of the Codeforces contest
+list and problemset; use --refresh to download them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dm, err := newDirectoryManager()
+		if err != nil {
+			return err
+		}
+		entries, err := dm.ListProblems()
+		if err != nil {
+			return err
+		}
+
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		api := newAPIClient()
+		api.SetOffline(!refresh)
+		items := upsolve.List(entries, upsolve.NewCatalog(contestList(api), problemset(api)))
+		if len(items) == 0 {
+			fmt.Println("Nothing to upsolve.")
+			return nil
+		}
+
+		root := viper.GetString("root")
+		fmt.Printf("%-10s%-8s%-13s%s\n", "Problem", "Rating", "Status", "Name")
+		for _, item := range items {
+			p := item.Entry.Problem
+			rating := "-"
+			if item.Rating > 0 {
+				rating = strconv.Itoa(item.Rating)
+			}
+			dir, err := filepath.Rel(root, item.Entry.Dir)
+			if err != nil {
+				dir = item.Entry.Dir
+			}
+			fmt.Printf("%-10s%-8s%-13s%s (%s)\n", upsolve.ProblemID(p.ContestCode, p.Index), rating, item.Reason, item.Entry.Metadata.Name, dir)
+		}
+		return nil
+	},
+}
+
+var upsolveMarkCmd = &cobra.Command{
+	Use:   "mark <problem> done|todo|skip",
+	Short: "Mark a problem as upsolved, to upsolve or skipped",
+	Long: `Marks a problem, given by its id (e.g. 1985C) or its directory, as done, todo or skip.
+The mark is kept in the problem's problem.json.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mark, err := upsolve.ParseMark(args[1])
+		if err != nil {
+			return err
+		}
+		dm, err := newDirectoryManager()
+		if err != nil {
+			return err
+		}
+		p, err := findProblem(dm, args[0])
+		if err != nil {
+			return err
+		}
+
+		err = dm.UpdateMetadata(p, func(meta *directorymanager.Metadata) {
+			meta.Upsolve = string(mark)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Marked %s as %s\n", upsolve.ProblemID(p.ContestCode, p.Index), mark)
+		return nil
+	},
+}
+
+// findProblem looks up an imported problem by its id, such as 1985C, or by
+// its directory.
+func findProblem(dm *directorymanager.DirectoryManager, arg string) (directorymanager.Problem, error) {
+	contestID, index, ok := upsolve.ParseProblemID(arg)
+	if !ok {
+		dir, err := filepath.Abs(arg)
+		if err != nil {
+			return directorymanager.Problem{}, err
+		}
+		return dm.ProblemKeyForDir(dir)
+	}
+
+	entries, err := dm.ListProblems()
+	if err != nil {
+		return directorymanager.Problem{}, err
+	}
+	for _, entry := range entries {
+		if entry.Problem.ContestCode == contestID && entry.Problem.Index == index {
+			return entry.Problem, nil
+		}
+	}
+	return directorymanager.Problem{}, fmt.Errorf("problem %s is not imported", arg)
+}
+
+// contestList returns the Codeforces contests, nil when they can't be
+// fetched, e.g. offline without a cached copy.
+func contestList(api *cfapi.Client) []cfapi.Contest {
+	contests, err := api.ContestList(false)
+	if err != nil {
+		logger.Debugf("No contest list: %v", err)
+		return nil
+	}
+	return contests
+}
+
+// problemset returns the Codeforces problemset, nil when it can't be
+// fetched, e.g. offline without a cached copy.
+func problemset(api *cfapi.Client) *cfapi.ProblemSet {
+	set, err := api.ProblemsetProblems(nil)
+	if err != nil {
+		logger.Debugf("No problemset: %v", err)
+		return nil
+	}
+	return set
+}
+
+// cachedProblem looks a problem up in the cached problemset, returning nil
+// when there is no cached copy or the problem isn't in it.
+func cachedProblem(contestID int, index string) *cfapi.Problem {
+	api := newAPIClient()
+	api.SetOffline(true)
+	set := problemset(api)
+	if set == nil {
+		return nil
+	}
+	for i, p := range set.Problems {
+		if p.ContestID == contestID && p.Index == index {
+			return &set.Problems[i]
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(upsolveCmd)
+	upsolveCmd.AddCommand(upsolveMarkCmd)
+
+	upsolveCmd.Flags().Bool("refresh", false, "download the contest list and problem ratings instead of using cached copies")
+}
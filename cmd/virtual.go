@@ -0,0 +1,184 @@
+/*
+Copyright © 2025 Priyanshu Sharma inbox.priyanshu@gmail.com
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/virtual"
+	"github.com/spf13/cobra"
+)
+
+// virtualCmd represents the virtual command
+var virtualCmd = &cobra.Command{
+	Use:   "virtual",
+	Short: "Time a virtual contest and track the pacing per problem",
+	Long: `Times a virtual contest started with 'virtual start'. While it runs, every problem of
+the contest gets the time it was first opened (imported, run or opened from the
+dashboard) and the time it first passed all its tests recorded in its problem.json.
+
+'virtual status' shows the remaining time and the problems so far, and 'virtual stop'
+ends the contest with a summary of the time spent on each problem.`,
+}
+
+var virtualStartCmd = &cobra.Command{
+	Use:   "start <contest-id>",
+	Short: "Start a virtual contest",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contestID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid contest id %q", args[0])
+		}
+		duration, _ := cmd.Flags().GetDuration("duration")
+		if duration <= 0 {
+			return errors.New("--duration must be positive")
+		}
+
+		path, err := virtualStatePath()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if s, err := virtual.Load(path); err == nil && s != nil && s.Running(now) {
+			return fmt.Errorf("virtual contest %d is running (%s left), stop it first", s.ContestID, virtual.FormatDuration(s.Remaining(now)))
+		}
+
+		s := virtual.NewSession(contestID, now, duration)
+		if err := virtual.Save(path, s); err != nil {
+			return err
+		}
+		fmt.Printf("Virtual contest %d started, ends at %s (%s)\n", contestID, s.End.Format("15:04"), virtual.FormatDuration(duration))
+		return nil
+	},
+}
+
+var virtualStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the remaining time and the problems of the virtual contest",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := loadVirtual()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if s.Running(now) {
+			fmt.Printf("Virtual contest %d: %s left, ends at %s\n\n", s.ContestID, virtual.FormatDuration(s.Remaining(now)), s.End.Format("15:04"))
+		} else {
+			fmt.Printf("Virtual contest %d ended at %s, run 'virtual stop' for the summary\n\n", s.ContestID, s.End.Format("15:04"))
+		}
+		return printVirtualSummary(s)
+	},
+}
+
+var virtualStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "End the virtual contest and summarize the time per problem",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := loadVirtual()
+		if err != nil {
+			return err
+		}
+
+		elapsed := min(time.Since(s.Start), s.End.Sub(s.Start))
+		fmt.Printf("Virtual contest %d, %s of %s\n\n", s.ContestID, virtual.FormatDuration(elapsed), virtual.FormatDuration(s.End.Sub(s.Start)))
+		if err := printVirtualSummary(s); err != nil {
+			return err
+		}
+
+		path, err := virtualStatePath()
+		if err != nil {
+			return err
+		}
+		return os.Remove(path)
+	},
+}
+
+func virtualStatePath() (string, error) {
+	dir, err := appConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "virtual.json"), nil
+}
+
+// loadVirtual returns the started virtual contest, failing without one.
+func loadVirtual() (*virtual.Session, error) {
+	path, err := virtualStatePath()
+	if err != nil {
+		return nil, err
+	}
+	s, err := virtual.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, errors.New("no virtual contest started, start one with 'virtual start <contest-id>'")
+	}
+	return s, nil
+}
+
+func printVirtualSummary(s *virtual.Session) error {
+	dm, err := newDirectoryManager()
+	if err != nil {
+		return err
+	}
+	entries, err := dm.ListProblems()
+	if err != nil {
+		return err
+	}
+	virtual.WriteSummary(os.Stdout, virtual.Summary(s, entries))
+	return nil
+}
+
+// stampVirtual records in meta that the problem p was opened, and solved if
+// solved is set, when it belongs to the running virtual contest. It reports
+// whether meta changed.
+func stampVirtual(p directorymanager.Problem, meta *directorymanager.Metadata, solved bool) bool {
+	path, err := virtualStatePath()
+	if err != nil {
+		return false
+	}
+	s, err := virtual.Load(path)
+	if err != nil {
+		logger.Warnf("Ignoring the virtual contest: %v", err)
+		return false
+	}
+	now := time.Now()
+	if s == nil || !s.Running(now) || s.ContestID != p.ContestCode {
+		return false
+	}
+	return s.Stamp(meta, now, solved)
+}
+
+// markOpened stamps the problem as opened for the running virtual contest.
+func markOpened(p directorymanager.Problem) {
+	dm, err := newDirectoryManager()
+	if err != nil {
+		return
+	}
+	meta, err := dm.ReadMetadata(p)
+	if err != nil || !stampVirtual(p, meta, false) {
+		return
+	}
+	if err := dm.WriteMetadata(p, meta); err != nil {
+		logger.Warnf("Failed to stamp the virtual contest in problem.json: %v", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(virtualCmd)
+	virtualCmd.AddCommand(virtualStartCmd)
+	virtualCmd.AddCommand(virtualStatusCmd)
+	virtualCmd.AddCommand(virtualStopCmd)
+
+	virtualStartCmd.Flags().Duration("duration", 2*time.Hour, "length of the contest, e.g. 2h or 2h15m")
+}
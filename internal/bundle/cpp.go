@@ -0,0 +1,291 @@
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	localIncludeRe = regexp.MustCompile(`^\s*#\s*include\s*"([^"]+)"`)
+	pragmaOnceRe   = regexp.MustCompile(`^\s*#\s*pragma\s+once\b`)
+	ifndefRe       = regexp.MustCompile(`^\s*#\s*ifndef\s+(\w+)`)
+	defineRe       = regexp.MustCompile(`^\s*#\s*define\s+(\w+)`)
+)
+
+// CPPBundler inlines local headers into a single C++ source file, the way the
+// preprocessor would, while leaving system headers (#include <...>) alone.
+type CPPBundler struct {
+	includePaths  []string
+	stripComments bool
+	logger        *log.Logger
+}
+
+func NewCPPBundler(includePaths []string, stripComments bool, logger *log.Logger) *CPPBundler {
+	return &CPPBundler{
+		includePaths:  includePaths,
+		stripComments: stripComments,
+		logger:        logger,
+	}
+}
+
+// cppState tracks what has been inlined while bundling one file.
+type cppState struct {
+	once   map[string]bool // headers with #pragma once already inlined
+	guards map[string]bool // include guard macros already defined
+	stack  []string        // headers being expanded, to report include cycles
+}
+
+// Bundle returns the contents of the source file at path with every quoted
+// include expanded recursively. Quoted includes are looked up next to the
+// including file first and then in the include paths. Headers marked with
+// #pragma once, or protected by an include guard, are inlined only once.
+func (b *CPPBundler) Bundle(path string) ([]byte, error) {
+	state := &cppState{once: make(map[string]bool), guards: make(map[string]bool)}
+
+	var out bytes.Buffer
+	if err := b.expand(&out, path, state, true); err != nil {
+		return nil, err
+	}
+	return squeezeBlankLines(out.Bytes()), nil
+}
+
+func (b *CPPBundler) expand(out *bytes.Buffer, path string, state *cppState, main bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for _, p := range state.stack {
+		if p == abs {
+			return fmt.Errorf("include cycle: %s", strings.Join(append(state.stack, abs), " -> "))
+		}
+	}
+
+	src, err := os.ReadFile(abs)
+	if err != nil {
+		return err
+	}
+	if b.stripComments {
+		src = StripCComments(src)
+	}
+
+	lines := splitLines(src)
+	if !main {
+		if hasPragmaOnce(lines) {
+			if state.once[abs] {
+				return nil
+			}
+			state.once[abs] = true
+		}
+		if guard := includeGuard(lines); guard != "" {
+			if state.guards[guard] {
+				return nil
+			}
+			state.guards[guard] = true
+		}
+		if !b.stripComments {
+			fmt.Fprintf(out, "// begin %s\n", b.displayName(abs))
+		}
+	}
+
+	state.stack = append(state.stack, abs)
+	defer func() { state.stack = state.stack[:len(state.stack)-1] }()
+
+	for _, line := range lines {
+		if pragmaOnceRe.MatchString(line) {
+			continue
+		}
+
+		m := localIncludeRe.FindStringSubmatch(line)
+		if m == nil {
+			out.WriteString(line)
+			out.WriteByte('\n')
+			continue
+		}
+
+		header, ok := b.resolve(filepath.Dir(abs), m[1])
+		if !ok {
+			b.logger.Printf("WARN: %s: could not find %q in the include paths, keeping the include", path, m[1])
+			out.WriteString(line)
+			out.WriteByte('\n')
+			continue
+		}
+		if err := b.expand(out, header, state, false); err != nil {
+			return err
+		}
+	}
+
+	if !main && !b.stripComments {
+		fmt.Fprintf(out, "// end %s\n", b.displayName(abs))
+	}
+	return nil
+}
+
+// resolve finds a quoted include relative to dir or one of the include paths.
+func (b *CPPBundler) resolve(dir, name string) (string, bool) {
+	for _, base := range append([]string{dir}, b.includePaths...) {
+		candidate := filepath.Join(base, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// displayName shortens a header path relative to the include path it was found in.
+func (b *CPPBundler) displayName(path string) string {
+	for _, base := range b.includePaths {
+		absBase, err := filepath.Abs(base)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(absBase, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.Base(path)
+}
+
+func hasPragmaOnce(lines []string) bool {
+	for _, line := range lines {
+		if pragmaOnceRe.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// includeGuard returns the macro of a classic "#ifndef X / #define X" guard
+// opening the file, ignoring blank lines and line comments before it.
+func includeGuard(lines []string) string {
+	var directives []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		directives = append(directives, trimmed)
+		if len(directives) == 2 {
+			break
+		}
+	}
+	if len(directives) < 2 {
+		return ""
+	}
+	ifndef := ifndefRe.FindStringSubmatch(directives[0])
+	define := defineRe.FindStringSubmatch(directives[1])
+	if ifndef == nil || define == nil || ifndef[1] != define[1] {
+		return ""
+	}
+	return ifndef[1]
+}
+
+func splitLines(src []byte) []string {
+	var lines []string
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimRight(sc.Text(), "\r"))
+	}
+	return lines
+}
+
+// squeezeBlankLines collapses runs of blank lines into one.
+func squeezeBlankLines(src []byte) []byte {
+	var out bytes.Buffer
+	blank := false
+	for _, line := range splitLines(src) {
+		if strings.TrimSpace(line) == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+// StripCComments removes // and /* */ comments from C or C++ source, leaving
+// string and character literals intact. Line breaks inside block comments are
+// kept so line-based directives stay on their own lines, and trailing spaces
+// left behind are trimmed.
+func StripCComments(src []byte) []byte {
+	var out bytes.Buffer
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				if src[i] == '\\' && i+1 < len(src) && src[i+1] == '\n' {
+					i++
+				}
+				i++
+			}
+			if i < len(src) {
+				out.WriteByte('\n')
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			out.WriteByte(' ')
+			for i += 2; i < len(src) && !(src[i] == '*' && i+1 < len(src) && src[i+1] == '/'); i++ {
+				if src[i] == '\n' {
+					out.WriteByte('\n')
+				}
+			}
+			i++
+		case c == 'R' && i+1 < len(src) && src[i+1] == '"' && (i == 0 || !isIdentByte(src[i-1])):
+			end := rawStringEnd(src, i+1)
+			out.Write(src[i:end])
+			i = end - 1
+		case c == '"' || c == '\'' && (i == 0 || !isIdentByte(src[i-1])): // not a digit separator as in 1'000
+			j := i + 1
+			for j < len(src) && src[j] != c && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				j = len(src) - 1
+			}
+			out.Write(src[i : j+1])
+			i = j
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// rawStringEnd returns the index just past a raw string literal R"delim(...)delim"
+// whose opening quote is at quote.
+func rawStringEnd(src []byte, quote int) int {
+	open := bytes.IndexByte(src[quote:], '(')
+	if open < 0 {
+		return len(src)
+	}
+	delim := src[quote+1 : quote+open]
+	closing := append(append([]byte(")"), delim...), '"')
+	end := bytes.Index(src[quote+open:], closing)
+	if end < 0 {
+		return len(src)
+	}
+	return quote + open + end + len(closing)
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
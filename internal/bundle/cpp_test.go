@@ -0,0 +1,95 @@
+package bundle
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating %s failed: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("writing %s failed: %v", path, err)
+		}
+	}
+}
+
+func TestCPPBundler_Bundle(t *testing.T) {
+	libDir := t.TempDir()
+	problemDir := t.TempDir()
+
+	writeFiles(t, libDir, map[string]string{
+		"lib/segtree.hpp": "#pragma once\n#include <vector>\n#include \"lib/util.hpp\"\nstruct SegTree {};\n",
+		"lib/util.hpp":    "#ifndef UTIL_HPP\n#define UTIL_HPP\nint util() { return 1; }\n#endif\n",
+		"lib/dsu.hpp":     "#pragma once\n#include \"util.hpp\"\nstruct DSU {};\n",
+	})
+	writeFiles(t, problemDir, map[string]string{
+		"main.cpp": "#include <bits/stdc++.h>\n#include \"lib/segtree.hpp\"\n#include \"lib/dsu.hpp\"\n#include \"lib/segtree.hpp\"\nint main() {}\n",
+	})
+
+	b := NewCPPBundler([]string{libDir}, false, log.New(os.Stderr, "", 0))
+	out, err := b.Bundle(filepath.Join(problemDir, "main.cpp"))
+	if err != nil {
+		t.Fatalf("Bundle failed: %v", err)
+	}
+	src := string(out)
+
+	for _, want := range []string{"#include <bits/stdc++.h>", "#include <vector>", "struct SegTree {};", "struct DSU {};", "// begin lib/segtree.hpp"} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected bundle to contain %q, got:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, `#include "`) {
+		t.Errorf("expected all local includes to be expanded, got:\n%s", src)
+	}
+	if strings.Contains(src, "#pragma once") {
+		t.Errorf("expected #pragma once to be dropped, got:\n%s", src)
+	}
+	if n := strings.Count(src, "struct SegTree"); n != 1 {
+		t.Errorf("expected segtree.hpp once, found %d times", n)
+	}
+	if n := strings.Count(src, "int util()"); n != 1 {
+		t.Errorf("expected the guarded util.hpp once, found %d times", n)
+	}
+}
+
+func TestCPPBundler_Cycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.cpp": "#include \"a.hpp\"\n",
+		"a.hpp":    "#include \"b.hpp\"\n",
+		"b.hpp":    "#include \"a.hpp\"\n",
+	})
+
+	b := NewCPPBundler(nil, false, log.New(os.Stderr, "", 0))
+	if _, err := b.Bundle(filepath.Join(dir, "main.cpp")); err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("expected an include cycle error, got %v", err)
+	}
+}
+
+func TestStripCComments(t *testing.T) {
+	src := `#include <cstdio> // io
+/* block
+comment */int main() {
+    const char *s = "// not a comment"; // trailing
+    char c = '/'; int n = 1'000; /* inline */ return 0;
+}
+`
+	want := `#include <cstdio>
+
+int main() {
+    const char *s = "// not a comment";
+    char c = '/'; int n = 1'000;   return 0;
+}
+`
+	if got := string(StripCComments([]byte(src))); got != want {
+		t.Errorf("unexpected result:\n%s\nwant:\n%s", got, want)
+	}
+}
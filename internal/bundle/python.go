@@ -0,0 +1,295 @@
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	pyImportRe     = regexp.MustCompile(`^\s*import\s+(.+)$`)
+	pyFromImportRe = regexp.MustCompile(`^\s*from\s+(\.*)([\w.]*)\s+import\s+\(?([^)#]*)`)
+	pyFutureRe     = regexp.MustCompile(`^from\s+__future__\s+import\b`)
+)
+
+// pyLoader is prepended to bundled scripts. It installs an import hook serving
+// the embedded modules, so they are imported lazily, in the same order and with
+// the same __name__ as when they were separate files.
+const pyLoader = `import sys as _cfcli_sys
+import importlib.abc as _cfcli_abc
+import importlib.util as _cfcli_util
+
+
+class _CfcliBundledModules(_cfcli_abc.MetaPathFinder, _cfcli_abc.Loader):
+    modules = {
+%s    }
+
+    def find_spec(self, name, path=None, target=None):
+        if name not in self.modules:
+            return None
+        return _cfcli_util.spec_from_loader(name, self, is_package=self.modules[name][0])
+
+    def create_module(self, spec):
+        return None
+
+    def exec_module(self, module):
+        source = self.modules[module.__name__][1]
+        exec(compile(source, "<bundled %%s>" %% module.__name__, "exec"), module.__dict__)
+
+
+_cfcli_sys.meta_path.insert(0, _CfcliBundledModules())
+`
+
+// PythonBundler embeds the local modules a Python script imports, directly
+// or through other local modules, into a single script.
+type PythonBundler struct {
+	libraryDir string
+	logger     *log.Logger
+}
+
+func NewPythonBundler(libraryDir string, logger *log.Logger) *PythonBundler {
+	return &PythonBundler{
+		libraryDir: libraryDir,
+		logger:     logger,
+	}
+}
+
+type pyModule struct {
+	name string
+	path string // empty for a namespace package without __init__.py
+	pkg  bool
+}
+
+type pyState struct {
+	main    string
+	roots   []string
+	modules map[string]*pyModule
+	order   []string
+}
+
+// Bundle returns the script at path preceded by the modules it imports from
+// the script's directory or the library directory. Modules that are not
+// found there, such as the standard library, are left to Python.
+func (b *PythonBundler) Bundle(path string) ([]byte, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	src, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &pyState{
+		main:    abs,
+		roots:   []string{filepath.Dir(abs)},
+		modules: make(map[string]*pyModule),
+	}
+	if b.libraryDir != "" {
+		state.roots = append(state.roots, b.libraryDir)
+	}
+
+	for _, name := range pyImports(string(src), "") {
+		if err := b.add(state, name); err != nil {
+			return nil, err
+		}
+	}
+	if len(state.order) == 0 {
+		return src, nil
+	}
+
+	var modules strings.Builder
+	for _, name := range state.order {
+		m := state.modules[name]
+		source := ""
+		if m.path != "" {
+			data, err := os.ReadFile(m.path)
+			if err != nil {
+				return nil, err
+			}
+			source = string(data)
+		}
+		pkg := "False"
+		if m.pkg {
+			pkg = "True"
+		}
+		fmt.Fprintf(&modules, "        %s: (%s, %s),\n", strconv.Quote(name), pkg, strconv.Quote(source))
+	}
+
+	// A __future__ import must stay the first statement of the script.
+	header, body := splitPyHeader(string(src))
+
+	var out bytes.Buffer
+	out.WriteString(header)
+	fmt.Fprintf(&out, pyLoader, modules.String())
+	out.WriteString("\n\n")
+	out.WriteString(body)
+	return out.Bytes(), nil
+}
+
+// add records the local module name, its parent packages and, recursively,
+// the local modules it imports. Dependencies come before their importers in
+// state.order.
+func (b *PythonBundler) add(state *pyState, name string) error {
+	if _, ok := state.modules[name]; ok {
+		return nil
+	}
+
+	m, err := b.find(state, name)
+	if err != nil || m == nil {
+		return err
+	}
+
+	if parent, _, ok := cutLast(name, "."); ok {
+		if err := b.add(state, parent); err != nil {
+			return err
+		}
+		if _, ok := state.modules[parent]; !ok {
+			// The module was found but its parent wasn't, e.g. a namespace
+			// package directory without __init__.py.
+			state.modules[parent] = &pyModule{name: parent, pkg: true}
+			state.order = append(state.order, parent)
+		}
+	}
+
+	state.modules[name] = m
+	b.logger.Printf("Inlining module %s", name)
+	if m.path != "" {
+		src, err := os.ReadFile(m.path)
+		if err != nil {
+			return err
+		}
+		// Relative imports are resolved against the package containing the
+		// module, or the module itself for a package's __init__.py.
+		pkg := name
+		if !m.pkg {
+			var found bool
+			if pkg, _, found = cutLast(name, "."); !found {
+				pkg = ""
+			}
+		}
+		for _, dep := range pyImports(string(src), pkg) {
+			if err := b.add(state, dep); err != nil {
+				return err
+			}
+		}
+	}
+	state.order = append(state.order, name)
+	return nil
+}
+
+// find looks for a module in the search roots, returning nil if it is not local.
+func (b *PythonBundler) find(state *pyState, name string) (*pyModule, error) {
+	rel := filepath.Join(strings.Split(name, ".")...)
+	for _, root := range state.roots {
+		file := filepath.Join(root, rel+".py")
+		if file == state.main {
+			continue
+		}
+		if isFile(file) {
+			return &pyModule{name: name, path: file}, nil
+		}
+		init := filepath.Join(root, rel, "__init__.py")
+		if isFile(init) {
+			return &pyModule{name: name, path: init, pkg: true}, nil
+		}
+		if info, err := os.Stat(filepath.Join(root, rel)); err == nil && info.IsDir() && strings.Contains(name, ".") {
+			// Only nested directories count as namespace packages, so that a
+			// stray top-level directory doesn't shadow an installed package.
+			return &pyModule{name: name, pkg: true}, nil
+		} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// pyImports lists the modules imported by src, resolving relative imports
+// against pkg. For "from a import b" both a and a.b are listed, since b may be
+// a submodule; names that aren't local modules are skipped by the caller.
+func pyImports(src, pkg string) []string {
+	var names []string
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if m := pyFromImportRe.FindStringSubmatch(line); m != nil {
+			base := m[2]
+			if dots := len(m[1]); dots > 0 {
+				base = resolveRelative(pkg, dots, m[2])
+				if base == "" {
+					continue
+				}
+			}
+			if base == "__future__" {
+				continue
+			}
+			names = append(names, base)
+			for _, item := range strings.Split(m[3], ",") {
+				if fields := strings.Fields(item); len(fields) > 0 && fields[0] != "*" {
+					names = append(names, base+"."+fields[0])
+				}
+			}
+			continue
+		}
+		if m := pyImportRe.FindStringSubmatch(line); m != nil {
+			imports, _, _ := strings.Cut(m[1], "#")
+			for _, item := range strings.Split(imports, ",") {
+				if fields := strings.Fields(item); len(fields) > 0 {
+					names = append(names, fields[0])
+				}
+			}
+		}
+	}
+	return names
+}
+
+// resolveRelative turns a relative import with the given number of leading
+// dots into an absolute module name, or "" if it leaves the top-level package.
+func resolveRelative(pkg string, dots int, name string) string {
+	if pkg == "" {
+		return ""
+	}
+	parts := strings.Split(pkg, ".")
+	if dots-1 >= len(parts) {
+		return ""
+	}
+	base := strings.Join(parts[:len(parts)-(dots-1)], ".")
+	if name == "" {
+		return base
+	}
+	return base + "." + name
+}
+
+// splitPyHeader separates the part of a script that must stay at its top,
+// a shebang line and any __future__ imports, from the rest.
+func splitPyHeader(src string) (header, body string) {
+	lines := strings.SplitAfter(src, "\n")
+	end := 0
+	if strings.HasPrefix(src, "#!") {
+		end = 1
+	}
+	for i, line := range lines {
+		if pyFutureRe.MatchString(line) {
+			end = i + 1
+		}
+	}
+	return strings.Join(lines[:end], ""), strings.Join(lines[end:], "")
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
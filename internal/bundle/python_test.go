@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPythonBundler_Bundle(t *testing.T) {
+	libDir := t.TempDir()
+	problemDir := t.TempDir()
+
+	writeFiles(t, libDir, map[string]string{
+		"algo/__init__.py": "",
+		"algo/math.py":     "from .util import double\n\ndef quad(x):\n    return double(double(x))\n",
+		"algo/util.py":     "def double(x):\n    return 2 * x\n\nif __name__ == \"__main__\":\n    print(\"util main\")\n",
+		"ds/dsu.py":        "import algo.util\n\nclass DSU:\n    pass\n",
+	})
+	writeFiles(t, problemDir, map[string]string{
+		"main.py": "from __future__ import annotations\nimport sys\nfrom algo.math import quad\nimport ds.dsu as dsu\n\nif __name__ == \"__main__\":\n    print(quad(int(sys.stdin.readline())), dsu.DSU.__name__)\n",
+	})
+
+	b := NewPythonBundler(libDir, log.New(os.Stderr, "", 0))
+	out, err := b.Bundle(filepath.Join(problemDir, "main.py"))
+	if err != nil {
+		t.Fatalf("Bundle failed: %v", err)
+	}
+	src := string(out)
+
+	if !strings.HasPrefix(src, "from __future__ import annotations\n") {
+		t.Errorf("expected the __future__ import to stay first, got:\n%s", src)
+	}
+	for _, name := range []string{`"algo"`, `"algo.math"`, `"algo.util"`, `"ds"`, `"ds.dsu"`} {
+		if n := strings.Count(src, name+": ("); n != 1 {
+			t.Errorf("expected module %s to be embedded once, found %d times", name, n)
+		}
+	}
+	if strings.Contains(src, `"sys": (`) {
+		t.Errorf("expected the standard library not to be embedded")
+	}
+
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not available")
+	}
+	bundled := filepath.Join(t.TempDir(), "submit.py")
+	if err := os.WriteFile(bundled, out, 0o644); err != nil {
+		t.Fatalf("writing bundle failed: %v", err)
+	}
+	cmd := exec.Command(python, bundled)
+	cmd.Stdin = strings.NewReader("3\n")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("running bundle failed: %v\n%s", err, output)
+	}
+	if got := strings.TrimSpace(string(output)); got != "12 DSU" {
+		t.Errorf("expected %q, got %q", "12 DSU", got)
+	}
+}
+
+func TestPyImports(t *testing.T) {
+	src := "import os, lib.a as a  # comment\nfrom . import b\nfrom ..c import d, e\nfrom f import (g,\n"
+	got := pyImports(src, "pkg.sub")
+	want := []string{"os", "lib.a", "pkg.sub", "pkg.sub.b", "pkg.c", "pkg.c.d", "pkg.c.e", "f", "f.g"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("pyImports = %v, want %v", got, want)
+	}
+}
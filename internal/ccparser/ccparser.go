@@ -11,7 +11,9 @@ import (
 )
 
 type Problem struct {
+	Judge        string
 	ContestCode  int
+	Index        string
 	ProblemCode  string
 	TestCases    []execution.TestCase
 	URL          string
@@ -85,7 +87,9 @@ func (s *Parser) Parse(ccp *CCProblem) (*Problem, error) {
 	}
 
 	problem := Problem{
+		Judge:        judgeName(probURL.Hostname()),
 		ContestCode:  contestCode,
+		Index:        problemIndex,
 		ProblemCode:  problemCode,
 		TestCases:    testCases,
 		URL:          ccp.URL,
@@ -139,3 +143,11 @@ func normalizeProblemCode(index string, name string) string {
 	return fmt.Sprintf("%s_%s", index, nameSlug)
 }
 
+// judgeName turns a host like "codeforces.com" into "codeforces".
+func judgeName(host string) string {
+	host = strings.TrimPrefix(host, "www.")
+	if idx := strings.Index(host, "."); idx != -1 {
+		host = host[:idx]
+	}
+	return host
+}
@@ -36,6 +36,14 @@ func TestParse_ValidContestProblem(t *testing.T) {
 		t.Errorf("Expected ProblemCode A_Sum_of_Two_Numbers, got %s", problem.ProblemCode)
 	}
 
+	if problem.Index != "A" {
+		t.Errorf("Expected Index A, got %s", problem.Index)
+	}
+
+	if problem.Judge != "codeforces" {
+		t.Errorf("Expected Judge codeforces, got %s", problem.Judge)
+	}
+
 	if len(problem.TestCases) != 2 {
 		t.Errorf("Expected 2 test cases, got %d", len(problem.TestCases))
 	}
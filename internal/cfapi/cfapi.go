@@ -0,0 +1,405 @@
+package cfapi
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the root of the public Codeforces API.
+const DefaultBaseURL = "https://codeforces.com/api"
+
+// DefaultInterval is the minimum time between two requests; Codeforces allows
+// one call every two seconds.
+const DefaultInterval = 2 * time.Second
+
+// ErrNotCached is returned in offline mode for requests without a cached response.
+var ErrNotCached = errors.New("response not cached")
+
+// APIError is a request the API answered with status FAILED.
+type APIError struct {
+	Method  string
+	Comment string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Method, e.Comment)
+}
+
+// cacheTTL is how long a cached response is used instead of calling the API.
+// Responses of other methods are cached too, but only used offline or when
+// the API can't be reached.
+var cacheTTL = map[string]time.Duration{
+	"contest.list":        time.Hour,
+	"problemset.problems": 24 * time.Hour,
+}
+
+// Client calls the Codeforces API. Requests are spaced out to respect the
+// rate limit, retried on transient failures and their results cached on disk.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	apiSecret  string
+	cacheDir   string
+	offline    bool
+	interval   time.Duration
+	retries    int
+	logger     *log.Logger
+
+	mu          sync.Mutex
+	lastRequest time.Time
+
+	// Replaced in tests.
+	now   func() time.Time
+	sleep func(time.Duration)
+	nonce func() string
+}
+
+func NewClient(baseURL string, logger *log.Logger) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		interval:   DefaultInterval,
+		retries:    3,
+		logger:     logger,
+		now:        time.Now,
+		sleep:      time.Sleep,
+		nonce:      randomNonce,
+	}
+}
+
+// SetCredentials signs every request with an API key from
+// https://codeforces.com/settings/api, giving access to private data such as
+// the user's own submissions in running contests.
+func (c *Client) SetCredentials(key, secret string) {
+	c.apiKey = key
+	c.apiSecret = secret
+}
+
+// SetCacheDir stores responses in dir. An empty dir disables caching.
+func (c *Client) SetCacheDir(dir string) {
+	c.cacheDir = dir
+}
+
+// SetOffline answers every request from the cache, failing with ErrNotCached
+// for responses that were never fetched.
+func (c *Client) SetOffline(offline bool) {
+	c.offline = offline
+}
+
+// SetInterval changes the minimum time between requests.
+func (c *Client) SetInterval(d time.Duration) {
+	c.interval = d
+}
+
+// SetRetries changes how often a failed request is retried.
+func (c *Client) SetRetries(n int) {
+	c.retries = n
+}
+
+// ContestList returns all contests, or all gym contests with gym.
+func (c *Client) ContestList(gym bool) ([]Contest, error) {
+	var contests []Contest
+	err := c.call("contest.list", url.Values{"gym": {strconv.FormatBool(gym)}}, &contests)
+	return contests, err
+}
+
+type StandingsOptions struct {
+	From           int // 1-based, 0 for the default
+	Count          int // 0 for all rows
+	Handles        []string
+	ShowUnofficial bool
+}
+
+func (c *Client) ContestStandings(contestID int, opts StandingsOptions) (*Standings, error) {
+	params := url.Values{"contestId": {strconv.Itoa(contestID)}}
+	if opts.From > 0 {
+		params.Set("from", strconv.Itoa(opts.From))
+	}
+	if opts.Count > 0 {
+		params.Set("count", strconv.Itoa(opts.Count))
+	}
+	if len(opts.Handles) > 0 {
+		params.Set("handles", strings.Join(opts.Handles, ";"))
+	}
+	if opts.ShowUnofficial {
+		params.Set("showUnofficial", "true")
+	}
+
+	var standings Standings
+	if err := c.call("contest.standings", params, &standings); err != nil {
+		return nil, err
+	}
+	return &standings, nil
+}
+
+// ProblemsetProblems returns the problems of the problemset, restricted to
+// problems having all of tags when given.
+func (c *Client) ProblemsetProblems(tags []string) (*ProblemSet, error) {
+	params := url.Values{}
+	if len(tags) > 0 {
+		params.Set("tags", strings.Join(tags, ";"))
+	}
+
+	var set ProblemSet
+	if err := c.call("problemset.problems", params, &set); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// UserStatus returns the submissions of handle, newest first. from is
+// 1-based; count 0 returns all of them.
+func (c *Client) UserStatus(handle string, from, count int) ([]Submission, error) {
+	params := url.Values{"handle": {handle}}
+	setRange(params, from, count)
+
+	var submissions []Submission
+	err := c.call("user.status", params, &submissions)
+	return submissions, err
+}
+
+func (c *Client) UserRating(handle string) ([]RatingChange, error) {
+	var changes []RatingChange
+	err := c.call("user.rating", url.Values{"handle": {handle}}, &changes)
+	return changes, err
+}
+
+// ContestStatus returns the submissions in a contest, newest first, only the
+// ones of handle when it is not empty.
+func (c *Client) ContestStatus(contestID int, handle string, from, count int) ([]Submission, error) {
+	params := url.Values{"contestId": {strconv.Itoa(contestID)}}
+	if handle != "" {
+		params.Set("handle", handle)
+	}
+	setRange(params, from, count)
+
+	var submissions []Submission
+	err := c.call("contest.status", params, &submissions)
+	return submissions, err
+}
+
+func setRange(params url.Values, from, count int) {
+	if from > 0 {
+		params.Set("from", strconv.Itoa(from))
+	}
+	if count > 0 {
+		params.Set("count", strconv.Itoa(count))
+	}
+}
+
+type envelope struct {
+	Status  string          `json:"status"`
+	Comment string          `json:"comment"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// call fetches the result of method into out, going through the cache.
+func (c *Client) call(method string, params url.Values, out any) error {
+	cachePath := c.cachePath(method, params)
+
+	if c.offline {
+		data, _, err := c.readCache(cachePath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", method, ErrNotCached)
+		}
+		return json.Unmarshal(data, out)
+	}
+
+	if data, fetched, err := c.readCache(cachePath); err == nil && c.now().Sub(fetched) < cacheTTL[method] {
+		c.logger.Printf("Using cached %s from %s", method, fetched.Format(time.RFC3339))
+		return json.Unmarshal(data, out)
+	}
+
+	result, err := c.fetch(method, params)
+	if err != nil {
+		var apiErr *APIError
+		if data, fetched, cacheErr := c.readCache(cachePath); cacheErr == nil && !errors.As(err, &apiErr) {
+			c.logger.Printf("WARN: %v, using the response cached at %s", err, fetched.Format(time.RFC3339))
+			return json.Unmarshal(data, out)
+		}
+		return err
+	}
+
+	if err := json.Unmarshal(result, out); err != nil {
+		return fmt.Errorf("%s: decoding result: %w", method, err)
+	}
+	c.writeCache(cachePath, result)
+	return nil
+}
+
+// fetch performs the request, retrying network errors, server errors and
+// rate limit rejections with an increasing delay.
+func (c *Client) fetch(method string, params url.Values) (json.RawMessage, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			delay := time.Duration(attempt) * c.interval
+			c.logger.Printf("WARN: %v, retrying in %s", lastErr, delay)
+			c.sleep(delay)
+		}
+
+		result, retry, err := c.do(method, params)
+		if err == nil {
+			return result, nil
+		}
+		if !retry {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (c *Client) do(method string, params url.Values) (result json.RawMessage, retry bool, err error) {
+	c.wait()
+
+	reqURL := fmt.Sprintf("%s/%s?%s", c.baseURL, method, c.sign(method, params).Encode())
+	c.logger.Printf("GET %s/%s?%s", c.baseURL, method, params.Encode())
+
+	resp, err := c.httpClient.Get(reqURL)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s: reading response: %w", method, err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return nil, retry, fmt.Errorf("%s: unexpected response (%s)", method, resp.Status)
+	}
+	if env.Status != "OK" {
+		apiErr := &APIError{Method: method, Comment: env.Comment}
+		return nil, strings.Contains(env.Comment, "Call limit exceeded"), apiErr
+	}
+	return env.Result, false, nil
+}
+
+// wait blocks until the rate limit allows the next request.
+func (c *Client) wait() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.lastRequest.IsZero() {
+		if d := c.interval - c.now().Sub(c.lastRequest); d > 0 {
+			c.sleep(d)
+		}
+	}
+	c.lastRequest = c.now()
+}
+
+// sign adds the apiKey, time and apiSig parameters when credentials are set,
+// as described at https://codeforces.com/apiHelp.
+func (c *Client) sign(method string, params url.Values) url.Values {
+	if c.apiKey == "" {
+		return params
+	}
+
+	signed := url.Values{}
+	for k, v := range params {
+		signed[k] = v
+	}
+	signed.Set("apiKey", c.apiKey)
+	signed.Set("time", strconv.FormatInt(c.now().Unix(), 10))
+
+	type pair struct{ key, value string }
+	var pairs []pair
+	for k, values := range signed {
+		for _, v := range values {
+			pairs = append(pairs, pair{k, v})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].key != pairs[j].key {
+			return pairs[i].key < pairs[j].key
+		}
+		return pairs[i].value < pairs[j].value
+	})
+	query := make([]string, len(pairs))
+	for i, p := range pairs {
+		query[i] = p.key + "=" + p.value
+	}
+
+	rnd := c.nonce()
+	sum := sha512.Sum512([]byte(fmt.Sprintf("%s/%s?%s#%s", rnd, method, strings.Join(query, "&"), c.apiSecret)))
+	signed.Set("apiSig", rnd+hex.EncodeToString(sum[:]))
+	return signed
+}
+
+func randomNonce() string {
+	const digits = "0123456789"
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "123456"
+	}
+	for i := range b {
+		b[i] = digits[int(b[i])%len(digits)]
+	}
+	return string(b)
+}
+
+// cachePath names the cache file of a request by its method and parameters.
+func (c *Client) cachePath(method string, params url.Values) string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(params.Encode()))
+	return filepath.Join(c.cacheDir, fmt.Sprintf("%s-%s.json", method, hex.EncodeToString(sum[:8])))
+}
+
+func (c *Client) readCache(path string) ([]byte, time.Time, error) {
+	if path == "" {
+		return nil, time.Time{}, ErrNotCached
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, info.ModTime(), nil
+}
+
+func (c *Client) writeCache(path string, data []byte) {
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		c.logger.Printf("WARN: failed to create the API cache: %v", err)
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		c.logger.Printf("WARN: failed to cache the response: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		c.logger.Printf("WARN: failed to cache the response: %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package cfapi
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fixtureServer serves testdata/<method>.json for /<method>, and
+// testdata/failed.json for the handle "nosuchuser". Requests are recorded.
+type fixtureServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*url.URL
+	failures int // number of requests to answer with 503 first
+}
+
+func newFixtureServer(t *testing.T) *fixtureServer {
+	t.Helper()
+	fs := &fixtureServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fs.mu.Lock()
+		fs.requests = append(fs.requests, r.URL)
+		fail := fs.failures > 0
+		if fail {
+			fs.failures--
+		}
+		fs.mu.Unlock()
+
+		if fail {
+			http.Error(w, "<html>Service Unavailable</html>", http.StatusServiceUnavailable)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/") + ".json"
+		if r.URL.Query().Get("handle") == "nosuchuser" {
+			name = "failed.json"
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func (fs *fixtureServer) count() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return len(fs.requests)
+}
+
+func newTestClient(fs *fixtureServer) *Client {
+	c := NewClient(fs.URL, log.New(os.Stderr, "TEST: ", 0))
+	c.SetInterval(0)
+	c.sleep = func(time.Duration) {}
+	return c
+}
+
+func TestClient_Methods(t *testing.T) {
+	fs := newFixtureServer(t)
+	c := newTestClient(fs)
+
+	contests, err := c.ContestList(false)
+	if err != nil || len(contests) != 2 || contests[1].ID != 2049 || contests[1].Phase != "FINISHED" {
+		t.Errorf("ContestList: got %+v, %v", contests, err)
+	}
+
+	standings, err := c.ContestStandings(2049, StandingsOptions{Count: 1})
+	if err != nil || len(standings.Problems) != 2 || standings.Rows[0].Party.Members[0].Handle != "tourist" {
+		t.Errorf("ContestStandings: got %+v, %v", standings, err)
+	}
+
+	set, err := c.ProblemsetProblems(nil)
+	if err != nil || len(set.Problems) != 2 || set.Problems[1].Rating != 800 || set.ProblemStatistics[0].SolvedCount != 15234 {
+		t.Errorf("ProblemsetProblems: got %+v, %v", set, err)
+	}
+
+	subs, err := c.UserStatus("tourist", 1, 10)
+	if err != nil || len(subs) != 2 || subs[0].Verdict != "OK" || subs[1].Problem.Index != "A" {
+		t.Errorf("UserStatus: got %+v, %v", subs, err)
+	}
+
+	changes, err := c.UserRating("tourist")
+	if err != nil || len(changes) != 1 || changes[0].NewRating != 3950 {
+		t.Errorf("UserRating: got %+v, %v", changes, err)
+	}
+
+	subs, err = c.ContestStatus(2049, "tourist", 0, 0)
+	if err != nil || len(subs) != 1 || subs[0].Verdict != "TESTING" {
+		t.Errorf("ContestStatus: got %+v, %v", subs, err)
+	}
+
+	q := fs.requests[1].Query()
+	if q.Get("contestId") != "2049" || q.Get("count") != "1" {
+		t.Errorf("unexpected contest.standings query: %s", fs.requests[1].RawQuery)
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	fs := newFixtureServer(t)
+	c := newTestClient(fs)
+
+	_, err := c.UserRating("nosuchuser")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !strings.Contains(apiErr.Comment, "not found") {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if fs.count() != 1 {
+		t.Errorf("expected API errors not to be retried, got %d requests", fs.count())
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	fs := newFixtureServer(t)
+	fs.failures = 2
+	c := newTestClient(fs)
+
+	if _, err := c.UserRating("tourist"); err != nil {
+		t.Fatalf("expected the request to succeed after retries, got %v", err)
+	}
+	if fs.count() != 3 {
+		t.Errorf("expected 3 requests, got %d", fs.count())
+	}
+
+	fs.failures = 10
+	c.SetRetries(1)
+	if _, err := c.UserRating("tourist"); err == nil {
+		t.Errorf("expected the request to fail once retries are exhausted")
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	fs := newFixtureServer(t)
+	c := newTestClient(fs)
+	c.SetInterval(2 * time.Second)
+
+	now := time.Unix(1700000000, 0)
+	c.now = func() time.Time { return now }
+	var slept []time.Duration
+	c.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		now = now.Add(d)
+	}
+
+	c.UserRating("tourist")
+	now = now.Add(500 * time.Millisecond)
+	c.UserRating("tourist")
+
+	if len(slept) != 1 || slept[0] != 1500*time.Millisecond {
+		t.Errorf("expected one wait of 1.5s, got %v", slept)
+	}
+}
+
+func TestClient_Signing(t *testing.T) {
+	fs := newFixtureServer(t)
+	c := newTestClient(fs)
+	c.SetCredentials("xxx", "yyy")
+	c.now = func() time.Time { return time.Unix(1234567890, 0) }
+	c.nonce = func() string { return "123456" }
+
+	if _, err := c.ContestStatus(566, "", 1, 1); err != nil {
+		t.Fatalf("ContestStatus failed: %v", err)
+	}
+
+	// The example from https://codeforces.com/apiHelp.
+	sum := sha512.Sum512([]byte("123456/contest.status?apiKey=xxx&contestId=566&count=1&from=1&time=1234567890#yyy"))
+	want := "123456" + hex.EncodeToString(sum[:])
+
+	q := fs.requests[0].Query()
+	if q.Get("apiKey") != "xxx" || q.Get("time") != "1234567890" {
+		t.Errorf("missing apiKey or time: %s", fs.requests[0].RawQuery)
+	}
+	if q.Get("apiSig") != want {
+		t.Errorf("apiSig = %s, want %s", q.Get("apiSig"), want)
+	}
+}
+
+func TestClient_Cache(t *testing.T) {
+	fs := newFixtureServer(t)
+	c := newTestClient(fs)
+	c.SetCacheDir(t.TempDir())
+
+	if _, err := c.ContestList(false); err != nil {
+		t.Fatalf("ContestList failed: %v", err)
+	}
+	if _, err := c.ContestList(false); err != nil {
+		t.Fatalf("ContestList failed: %v", err)
+	}
+	if fs.count() != 1 {
+		t.Errorf("expected the second contest.list to come from the cache, got %d requests", fs.count())
+	}
+
+	// user.rating is cached but not reused while online.
+	c.UserRating("tourist")
+	c.UserRating("tourist")
+	if fs.count() != 3 {
+		t.Errorf("expected user.rating to be fetched twice, got %d requests", fs.count())
+	}
+
+	c.SetOffline(true)
+	if changes, err := c.UserRating("tourist"); err != nil || len(changes) != 1 {
+		t.Errorf("expected the cached user.rating offline, got %v, %v", changes, err)
+	}
+	if _, err := c.UserStatus("tourist", 0, 0); !errors.Is(err, ErrNotCached) {
+		t.Errorf("expected ErrNotCached, got %v", err)
+	}
+	if fs.count() != 3 {
+		t.Errorf("expected no requests offline, got %d", fs.count()-3)
+	}
+}
+
+func TestClient_CacheFallback(t *testing.T) {
+	fs := newFixtureServer(t)
+	c := newTestClient(fs)
+	c.SetCacheDir(t.TempDir())
+	c.SetRetries(0)
+
+	if _, err := c.UserRating("tourist"); err != nil {
+		t.Fatalf("UserRating failed: %v", err)
+	}
+
+	fs.failures = 1
+	if changes, err := c.UserRating("tourist"); err != nil || len(changes) != 1 {
+		t.Errorf("expected the cached response when the API is down, got %v, %v", changes, err)
+	}
+}
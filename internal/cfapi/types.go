@@ -0,0 +1,100 @@
+package cfapi
+
+// The types below mirror the objects returned by the Codeforces API, see
+// https://codeforces.com/apiHelp/objects. Only the fields the CLI uses, or is
+// likely to use, are included.
+
+type Contest struct {
+	ID                  int    `json:"id"`
+	Name                string `json:"name"`
+	Type                string `json:"type"`
+	Phase               string `json:"phase"`
+	Frozen              bool   `json:"frozen"`
+	DurationSeconds     int64  `json:"durationSeconds"`
+	StartTimeSeconds    int64  `json:"startTimeSeconds"`
+	RelativeTimeSeconds int64  `json:"relativeTimeSeconds"`
+}
+
+type Problem struct {
+	ContestID      int      `json:"contestId"`
+	ProblemsetName string   `json:"problemsetName,omitempty"`
+	Index          string   `json:"index"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Points         float64  `json:"points,omitempty"`
+	Rating         int      `json:"rating,omitempty"`
+	Tags           []string `json:"tags"`
+}
+
+type ProblemStatistics struct {
+	ContestID   int    `json:"contestId"`
+	Index       string `json:"index"`
+	SolvedCount int    `json:"solvedCount"`
+}
+
+type Member struct {
+	Handle string `json:"handle"`
+}
+
+type Party struct {
+	ContestID        int      `json:"contestId"`
+	Members          []Member `json:"members"`
+	ParticipantType  string   `json:"participantType"`
+	Ghost            bool     `json:"ghost"`
+	StartTimeSeconds int64    `json:"startTimeSeconds,omitempty"`
+}
+
+type Submission struct {
+	ID                  int64   `json:"id"`
+	ContestID           int     `json:"contestId"`
+	CreationTimeSeconds int64   `json:"creationTimeSeconds"`
+	RelativeTimeSeconds int64   `json:"relativeTimeSeconds"`
+	Problem             Problem `json:"problem"`
+	Author              Party   `json:"author"`
+	ProgrammingLanguage string  `json:"programmingLanguage"`
+	// Verdict is empty while the submission waits in the queue, and
+	// "TESTING" while it is being judged.
+	Verdict             string  `json:"verdict"`
+	Testset             string  `json:"testset"`
+	PassedTestCount     int     `json:"passedTestCount"`
+	TimeConsumedMillis  int     `json:"timeConsumedMillis"`
+	MemoryConsumedBytes int64   `json:"memoryConsumedBytes"`
+	Points              float64 `json:"points,omitempty"`
+}
+
+type RatingChange struct {
+	ContestID               int    `json:"contestId"`
+	ContestName             string `json:"contestName"`
+	Handle                  string `json:"handle"`
+	Rank                    int    `json:"rank"`
+	RatingUpdateTimeSeconds int64  `json:"ratingUpdateTimeSeconds"`
+	OldRating               int    `json:"oldRating"`
+	NewRating               int    `json:"newRating"`
+}
+
+type ProblemResult struct {
+	Points                    float64 `json:"points"`
+	Penalty                   int     `json:"penalty"`
+	RejectedAttemptCount      int     `json:"rejectedAttemptCount"`
+	Type                      string  `json:"type"`
+	BestSubmissionTimeSeconds int64   `json:"bestSubmissionTimeSeconds"`
+}
+
+type RanklistRow struct {
+	Party          Party           `json:"party"`
+	Rank           int             `json:"rank"`
+	Points         float64         `json:"points"`
+	Penalty        int             `json:"penalty"`
+	ProblemResults []ProblemResult `json:"problemResults"`
+}
+
+type Standings struct {
+	Contest  Contest       `json:"contest"`
+	Problems []Problem     `json:"problems"`
+	Rows     []RanklistRow `json:"rows"`
+}
+
+type ProblemSet struct {
+	Problems          []Problem           `json:"problems"`
+	ProblemStatistics []ProblemStatistics `json:"problemStatistics"`
+}
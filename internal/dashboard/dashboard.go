@@ -0,0 +1,461 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Row is a problem shown on the dashboard.
+type Row struct {
+	Index   string
+	Name    string
+	Dir     string
+	Program string // path of the program file
+	LastRun *directorymanager.RunRecord
+	Missing bool // the program file does not exist
+	// Modified reports whether the program changed after the last test run.
+	Modified bool
+}
+
+// NewRow builds the row of an imported problem whose program file is
+// programFile, e.g. "main.cpp".
+func NewRow(entry directorymanager.ProblemEntry, programFile string) Row {
+	row := Row{
+		Index:   entry.Problem.Index,
+		Name:    entry.Metadata.Name,
+		Dir:     entry.Dir,
+		Program: filepath.Join(entry.Dir, programFile),
+		LastRun: entry.Metadata.LastRun,
+	}
+
+	info, err := os.Stat(row.Program)
+	switch {
+	case err != nil:
+		row.Missing = true
+	case row.LastRun != nil:
+		row.Modified = info.ModTime().After(row.LastRun.At)
+	}
+	return row
+}
+
+// Actions are run on the selected problem. The returned message is shown in
+// the status line.
+type Actions struct {
+	Run    func(row Row) (string, error)
+	Edit   func(row Row) error
+	Bundle func(row Row) (string, error)
+}
+
+type Options struct {
+	Title string
+	// Root is watched for newly imported problems, next to the directories
+	// of the problems listed.
+	Root    string
+	Load    func() ([]Row, error)
+	Actions Actions
+	// ForegroundEditor hands the terminal over to Actions.Edit.
+	ForegroundEditor bool
+}
+
+// Dashboard is a full-screen view of the problems of a contest, refreshed
+// whenever their files change.
+type Dashboard struct {
+	opts   Options
+	logger *log.Logger
+
+	rows     []Row
+	selected int
+	status   string
+
+	in  *os.File
+	out io.Writer
+
+	reader *keyReader
+}
+
+func New(opts Options, logger *log.Logger) *Dashboard {
+	return &Dashboard{
+		opts:   opts,
+		logger: logger,
+		in:     os.Stdin,
+		out:    os.Stdout,
+	}
+}
+
+// refreshDelay groups the bursts of file events caused by a single save or
+// test run into one refresh.
+const refreshDelay = 150 * time.Millisecond
+
+// Run shows the dashboard until the user quits.
+func (d *Dashboard) Run() error {
+	fd := int(d.in.Fd())
+	state, err := makeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("the dashboard needs an interactive terminal: %w", err)
+	}
+	fmt.Fprint(d.out, enterScreen)
+	defer func() {
+		fmt.Fprint(d.out, leaveScreen)
+		restore(fd, state)
+	}()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	d.reload(watcher)
+	d.status = "Ready"
+
+	d.reader = startKeyReader(fd)
+	defer func() { d.reader.stop() }()
+
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+
+	refresh := time.NewTimer(time.Hour)
+	refresh.Stop()
+
+	for {
+		d.render()
+		select {
+		case buf := <-d.reader.keys:
+			for _, key := range parseKeys(buf) {
+				if key == keyQuit {
+					return nil
+				}
+				if err := d.handle(key, fd, state, watcher); err != nil {
+					return err
+				}
+			}
+		case <-watcher.Events:
+			refresh.Reset(refreshDelay)
+		case err := <-watcher.Errors:
+			d.status = fmt.Sprintf("Watch error: %v", err)
+		case <-refresh.C:
+			d.reload(watcher)
+		case <-resize:
+		}
+	}
+}
+
+func (d *Dashboard) handle(key key, fd int, state *termState, watcher *fsnotify.Watcher) error {
+	switch key {
+	case keyUp:
+		if d.selected > 0 {
+			d.selected--
+		}
+		return nil
+	case keyDown:
+		if d.selected < len(d.rows)-1 {
+			d.selected++
+		}
+		return nil
+	}
+
+	if len(d.rows) == 0 {
+		return nil
+	}
+	row := d.rows[d.selected]
+
+	switch key {
+	case keyRun:
+		d.status = fmt.Sprintf("Running the tests of %s…", row.Index)
+		d.render()
+		d.status = actionStatus(row, d.opts.Actions.Run)
+	case keyBundle:
+		d.status = fmt.Sprintf("Bundling %s…", row.Index)
+		d.render()
+		d.status = actionStatus(row, d.opts.Actions.Bundle)
+	case keyEdit:
+		if !d.opts.ForegroundEditor {
+			d.status = actionStatus(row, func(row Row) (string, error) {
+				return fmt.Sprintf("Opened %s", filepath.Base(row.Program)), d.opts.Actions.Edit(row)
+			})
+			break
+		}
+
+		// Give the terminal to the editor until it exits.
+		d.reader.stop()
+		fmt.Fprint(d.out, leaveScreen)
+		restore(fd, state)
+		err := d.opts.Actions.Edit(row)
+		if _, rawErr := makeRaw(fd); rawErr != nil {
+			return rawErr
+		}
+		fmt.Fprint(d.out, enterScreen)
+		d.reader = startKeyReader(fd)
+		d.status = fmt.Sprintf("Edited %s", filepath.Base(row.Program))
+		if err != nil {
+			d.status = fmt.Sprintf("%s: %v", row.Index, err)
+		}
+	}
+	d.reload(watcher)
+	return nil
+}
+
+func actionStatus(row Row, action func(Row) (string, error)) string {
+	msg, err := action(row)
+	if err != nil {
+		return fmt.Sprintf("%s: %v", row.Index, err)
+	}
+	return fmt.Sprintf("%s: %s", row.Index, msg)
+}
+
+// reload reads the problems again and watches their directories, keeping the
+// selection on the same problem.
+func (d *Dashboard) reload(watcher *fsnotify.Watcher) {
+	rows, err := d.opts.Load()
+	if err != nil {
+		d.status = fmt.Sprintf("Failed to load the problems: %v", err)
+		return
+	}
+
+	selected := ""
+	if d.selected < len(d.rows) {
+		selected = d.rows[d.selected].Dir
+	}
+	d.rows = rows
+	d.selected = 0
+	for i, row := range rows {
+		if row.Dir == selected {
+			d.selected = i
+		}
+	}
+
+	dirs := []string{d.opts.Root}
+	for _, row := range rows {
+		dirs = append(dirs, row.Dir, filepath.Dir(row.Dir), filepath.Join(row.Dir, execution.TestsDir))
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+			d.logger.Printf("WARN: not watching %s: %v", dir, err)
+		}
+	}
+}
+
+func (d *Dashboard) render() {
+	width, height := 80, 24
+	if w, h, err := windowSize(int(d.in.Fd())); err == nil {
+		width, height = w, h
+	}
+	fmt.Fprint(d.out, Render(Screen{
+		Title:    d.opts.Title,
+		Rows:     d.rows,
+		Selected: d.selected,
+		Status:   d.status,
+		Width:    width,
+		Height:   height,
+	}))
+}
+
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l" // alternate screen, hidden cursor
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+	clearScreen = "\x1b[H\x1b[2J"
+
+	bold   = "\x1b[1m"
+	invert = "\x1b[7m"
+	red    = "\x1b[31m"
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+	faint  = "\x1b[2m"
+	reset  = "\x1b[0m"
+)
+
+// Screen is everything shown on the dashboard.
+type Screen struct {
+	Title    string
+	Rows     []Row
+	Selected int
+	Status   string
+	Width    int
+	Height   int
+}
+
+const help = "↑/↓ select  r run tests  e edit  b bundle  q quit"
+
+// Render draws the screen, clearing what was shown before.
+func Render(s Screen) string {
+	var b strings.Builder
+	b.WriteString(clearScreen)
+	fmt.Fprintf(&b, "%s%s%s\n\n", bold, s.Title, reset)
+
+	if len(s.Rows) == 0 {
+		b.WriteString("No problems imported yet.\n")
+	} else {
+		nameWidth := max(s.Width-2-4-16-10-10, 10)
+		fmt.Fprintf(&b, "%s  %-4s%-*s%-16s%-10s%-10s%s\n", faint, "", nameWidth, "Problem", "Tests", "Last run", "Program", reset)
+		for i, row := range s.Rows {
+			marker, style := "  ", ""
+			if i == s.Selected {
+				marker, style = "> ", invert
+			}
+			// Pad before coloring, escape sequences have no width.
+			tests, testsColor := testStatus(row.LastRun)
+			program, programColor := programStatus(row)
+			fmt.Fprintf(&b, "%s%s%-4s%-*s%s%s%-16s%s%-10s%s%-10s%s\n",
+				marker, style, row.Index,
+				nameWidth, truncate(strings.TrimPrefix(row.Name, row.Index+". "), nameWidth-1),
+				reset, testsColor, tests, reset,
+				lastRun(row.LastRun),
+				programColor, program, reset)
+		}
+	}
+
+	// Keep the status and help at the bottom.
+	for lines := strings.Count(b.String(), "\n"); lines < s.Height-3; lines++ {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\n%s\n%s%s%s", truncate(s.Status, s.Width), faint, truncate(help, s.Width), reset)
+	return b.String()
+}
+
+func testStatus(run *directorymanager.RunRecord) (string, string) {
+	switch {
+	case run == nil:
+		return "not run", faint
+	case run.Verdict == string(execution.VerdictCompileError):
+		return "compile error", red
+	case run.Verdict == string(execution.VerdictOK):
+		return fmt.Sprintf("passed %d/%d", run.Passed, run.Total), green
+	default:
+		return fmt.Sprintf("%s %d/%d", run.Verdict, run.Passed, run.Total), red
+	}
+}
+
+func lastRun(run *directorymanager.RunRecord) string {
+	if run == nil {
+		return "-"
+	}
+	return run.At.Local().Format("15:04:05")
+}
+
+func programStatus(row Row) (string, string) {
+	switch {
+	case row.Missing:
+		return "missing", red
+	case row.Modified:
+		return "modified", yellow
+	case row.LastRun == nil:
+		return "", ""
+	default:
+		return "tested", faint
+	}
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyRun
+	keyEdit
+	keyBundle
+	keyQuit
+)
+
+// parseKeys decodes the keys in a chunk of terminal input.
+func parseKeys(buf []byte) []key {
+	var keys []key
+	for i := 0; i < len(buf); i++ {
+		switch c := buf[i]; {
+		case c == 0x1b && i+2 < len(buf) && (buf[i+1] == '[' || buf[i+1] == 'O'):
+			switch buf[i+2] {
+			case 'A':
+				keys = append(keys, keyUp)
+			case 'B':
+				keys = append(keys, keyDown)
+			}
+			i += 2
+		case c == 'k':
+			keys = append(keys, keyUp)
+		case c == 'j':
+			keys = append(keys, keyDown)
+		case c == 'r' || c == '\r' || c == '\n':
+			keys = append(keys, keyRun)
+		case c == 'e':
+			keys = append(keys, keyEdit)
+		case c == 'b':
+			keys = append(keys, keyBundle)
+		case c == 'q' || c == 0x03 || c == 0x04: // q, Ctrl-C, Ctrl-D
+			keys = append(keys, keyQuit)
+		}
+	}
+	return keys
+}
+
+// keyReader reads the terminal in the background. Reads time out
+// regularly in raw mode, so the reader can be stopped before another
+// program takes over the terminal.
+type keyReader struct {
+	keys chan []byte
+	quit chan struct{}
+	done chan struct{}
+}
+
+func startKeyReader(fd int) *keyReader {
+	r := &keyReader{
+		keys: make(chan []byte),
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(r.done)
+		buf := make([]byte, 64)
+		for {
+			select {
+			case <-r.quit:
+				return
+			default:
+			}
+			n, err := readInput(fd, buf)
+			if err != nil {
+				time.Sleep(refreshDelay)
+				continue
+			}
+			if n == 0 {
+				continue
+			}
+			chunk := append([]byte(nil), buf[:n]...)
+			select {
+			case r.keys <- chunk:
+			case <-r.quit:
+				return
+			}
+		}
+	}()
+	return r
+}
+
+func (r *keyReader) stop() {
+	select {
+	case <-r.quit:
+	default:
+		close(r.quit)
+	}
+	<-r.done
+}
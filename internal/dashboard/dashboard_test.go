@@ -0,0 +1,86 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/ccparser"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+func TestNewRow(t *testing.T) {
+	dir := t.TempDir()
+	program := filepath.Join(dir, "main.cpp")
+	if err := os.WriteFile(program, []byte("int main() {}\n"), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	modified := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(program, modified, modified); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+
+	entry := directorymanager.ProblemEntry{
+		Problem: directorymanager.Problem{ContestCode: 1985, Index: "C"},
+		Dir:     dir,
+		Metadata: &directorymanager.Metadata{
+			CCProblem: ccparser.CCProblem{Name: "C. Good Prefixes"},
+			LastRun:   &directorymanager.RunRecord{At: modified.Add(-time.Minute), Verdict: "OK", Passed: 3, Total: 3},
+		},
+	}
+
+	row := NewRow(entry, "main.cpp")
+	if row.Index != "C" || row.Program != program || !row.Modified || row.Missing {
+		t.Errorf("unexpected row: %+v", row)
+	}
+
+	entry.Metadata.LastRun.At = modified.Add(time.Minute)
+	if row := NewRow(entry, "main.cpp"); row.Modified {
+		t.Errorf("expected a program tested after its last change not to be modified")
+	}
+	if row := NewRow(entry, "main.py"); !row.Missing {
+		t.Errorf("expected a missing program file to be reported")
+	}
+}
+
+func TestRender(t *testing.T) {
+	at := time.Date(2024, 6, 1, 14, 3, 12, 0, time.Local)
+	screen := Render(Screen{
+		Title: "Contest 1985",
+		Rows: []Row{
+			{Index: "A", Name: "A. Short Sort", LastRun: &directorymanager.RunRecord{At: at, Verdict: "OK", Passed: 2, Total: 2}},
+			{Index: "B", Name: "B. Good Kid", LastRun: &directorymanager.RunRecord{At: at, Verdict: "WA", Passed: 1, Total: 3}, Modified: true},
+			{Index: "C", Name: "C. Good Prefixes with a very long name that does not fit"},
+		},
+		Selected: 1,
+		Status:   "B: wrong answer",
+		Width:    60,
+		Height:   12,
+	})
+
+	for _, want := range []string{"Contest 1985", "Short Sort", "passed 2/2", "WA 1/3", "14:03:12", "modified", "not run", "B: wrong answer", "q quit"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("expected the screen to contain %q:\n%s", want, screen)
+		}
+	}
+	if !strings.Contains(screen, "> "+invert+"B") {
+		t.Errorf("expected B to be selected:\n%s", screen)
+	}
+	if strings.Contains(screen, "does not fit") {
+		t.Errorf("expected the long name to be truncated:\n%s", screen)
+	}
+	if lines := strings.Count(screen, "\n"); lines != 11 {
+		t.Errorf("expected the screen to fill 12 lines, got %d", lines+1)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\x1b[A\x1bOBrebq\x03"))
+	want := []key{keyDown, keyUp, keyDown, keyRun, keyEdit, keyBundle, keyQuit, keyQuit}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKeys = %v, want %v", got, want)
+	}
+}
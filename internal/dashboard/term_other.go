@@ -0,0 +1,22 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package dashboard
+
+import (
+	"errors"
+	"os"
+)
+
+var errUnsupported = errors.New("terminal control is not supported on this platform")
+
+type termState struct{}
+
+func makeRaw(fd int) (*termState, error) { return nil, errUnsupported }
+
+func restore(fd int, state *termState) error { return errUnsupported }
+
+func windowSize(fd int) (width, height int, err error) { return 0, 0, errUnsupported }
+
+func readInput(fd int, buf []byte) (int, error) { return 0, errUnsupported }
+
+func notifyResize(ch chan<- os.Signal) {}
@@ -1,87 +1,505 @@
 package directorymanager
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/ccparser"
 	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
 )
 
+// DefaultLayout reproduces the original <contest>/<problemCode> structure.
+const DefaultLayout = "{{.Contest}}/{{.Code}}"
+
+// MetadataFile is the name of the file holding a problem's metadata.
+const MetadataFile = "problem.json"
+
+// OverrideFile is the name of the per-directory configuration overrides.
+const OverrideFile = ".cfcli.yaml"
+
 type DirectoryManager struct {
 	logger   *log.Logger
 	rootPath string
+	layout   *template.Template
 }
 
 type Problem struct {
+	Judge       string
 	ContestCode int
+	Index       string
 	ProblemCode string
+	Year        int
+}
+
+// Metadata is the content of problem.json: the Competitive Companion payload
+// plus the fields maintained by codeforces-cli itself.
+type Metadata struct {
+	ccparser.CCProblem
+	ImportedAt  time.Time          `json:"importedAt"`
+	Tags        []string           `json:"tags,omitempty"`   // from the Codeforces problemset
+	Rating      int                `json:"rating,omitempty"` // from the Codeforces problemset
+	LastRun     *RunRecord         `json:"lastRun,omitempty"`
+	PassedAt    *time.Time         `json:"passedAt,omitempty"` // first run passing all tests
+	Upsolve     string             `json:"upsolve,omitempty"`  // done, todo or skip
+	Virtual     *VirtualRecord     `json:"virtual,omitempty"`
+	Submissions []SubmissionRecord `json:"submissions,omitempty"`
+}
+
+// RunRecord is the outcome of the latest local test run.
+type RunRecord struct {
+	At      time.Time `json:"at"`
+	Verdict string    `json:"verdict"` // OK, WA, RE, TLE or CE for a build failure
+	Passed  int       `json:"passed"`
+	Total   int       `json:"total"`
 }
 
-func (p Problem) RelativeDir() string {
-	return filepath.Join(strconv.Itoa(p.ContestCode), p.ProblemCode)
+// VirtualRecord is when a problem was first opened and first passed all its
+// tests during the virtual contest started at Start.
+type VirtualRecord struct {
+	Start    time.Time  `json:"start"`
+	OpenedAt time.Time  `json:"openedAt"`
+	SolvedAt *time.Time `json:"solvedAt,omitempty"`
+}
+
+// SubmissionRecord is the outcome of a submission made with codeforces-cli.
+type SubmissionRecord struct {
+	ID          int64     `json:"id"`
+	SubmittedAt time.Time `json:"submittedAt"`
+	Language    string    `json:"language"`
+	Verdict     string    `json:"verdict"`
+	PassedTests int       `json:"passedTests"`
+	TimeMs      int       `json:"timeMs"`
+	MemoryBytes int64     `json:"memoryBytes"`
+}
+
+// layoutData holds the variables available to a layout template.
+type layoutData struct {
+	Judge   string
+	Contest int
+	Index   string
+	Code    string
+	Year    int
+}
+
+// NewProblem builds the directory key for a parsed problem imported at the given time.
+func NewProblem(pp *ccparser.Problem, importedAt time.Time) Problem {
+	return Problem{
+		Judge:       pp.Judge,
+		ContestCode: pp.ContestCode,
+		Index:       pp.Index,
+		ProblemCode: pp.ProblemCode,
+		Year:        importedAt.Year(),
+	}
 }
 
 func NewDirectoryManager(root string, logger *log.Logger) *DirectoryManager {
 	return &DirectoryManager{
 		logger:   logger,
 		rootPath: filepath.Clean(root),
+		layout:   template.Must(parseLayout(DefaultLayout)),
 	}
 }
 
+// SetLayout changes the template used to place problems below root.
+func (d *DirectoryManager) SetLayout(layout string) error {
+	t, err := parseLayout(layout)
+	if err != nil {
+		return err
+	}
+	d.layout = t
+	return nil
+}
+
+// ValidateLayout reports whether layout is a usable layout template.
+func ValidateLayout(layout string) error {
+	_, err := parseLayout(layout)
+	return err
+}
+
+func parseLayout(layout string) (*template.Template, error) {
+	t, err := template.New("layout").Option("missingkey=error").Parse(layout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid layout %q: %w", layout, err)
+	}
+
+	// Render a sample so unknown fields are reported now rather than at import time.
+	sample := Problem{Judge: "codeforces", ContestCode: 1, Index: "A", ProblemCode: "A_Sample", Year: 2000}
+	if _, err := renderLayout(t, sample); err != nil {
+		return nil, fmt.Errorf("invalid layout %q: %w", layout, err)
+	}
+	return t, nil
+}
+
+func renderLayout(t *template.Template, p Problem) (string, error) {
+	data := layoutData{
+		Judge:   p.Judge,
+		Contest: p.ContestCode,
+		Index:   p.Index,
+		Code:    p.ProblemCode,
+		Year:    p.Year,
+	}
+	if data.Judge == "" {
+		data.Judge = "codeforces"
+	}
+	if data.Index == "" {
+		data.Index, _, _ = strings.Cut(p.ProblemCode, "_")
+	}
+	if data.Year == 0 {
+		data.Year = time.Now().Year()
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	rel := filepath.Clean(filepath.FromSlash(strings.TrimSpace(buf.String())))
+	if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("layout renders to %q, which is not below root", buf.String())
+	}
+	return rel, nil
+}
+
+// RelativeDir returns the problem directory relative to root.
+func (d *DirectoryManager) RelativeDir(p Problem) string {
+	rel, err := renderLayout(d.layout, p)
+	if err != nil {
+		d.logger.Printf("WARN: failed to render layout, using default: %s", err)
+		rel, _ = renderLayout(template.Must(parseLayout(DefaultLayout)), p)
+	}
+	return rel
+}
+
 func (d *DirectoryManager) FullProblemPath(p Problem) string {
-	return filepath.Join(d.rootPath, p.RelativeDir())
+	return filepath.Join(d.rootPath, d.RelativeDir(p))
 }
 
 func (d *DirectoryManager) EnsureDir(p Problem) (string, error) {
+	return d.ensureDir(nil, p)
+}
+
+func (d *DirectoryManager) ensureDir(j *journal, p Problem) (string, error) {
 	dir := d.FullProblemPath(p)
 	d.logger.Printf("Ensuring directory: %s", dir)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := j.mkdirAll(dir); err != nil {
 		return "", fmt.Errorf("failed to create directory: %w", err)
 	}
 	return dir, nil
 }
 
-func (d *DirectoryManager) WriteTestCases(p Problem, testCases []execution.TestCase, inputPrefix, outputPrefix string) error {
-	dir := d.FullProblemPath(p)
+// Test groups below the problem's tests directory. Samples come from the
+// problem statement and are managed by imports; custom tests belong to the
+// user and are never touched by an import.
+const (
+	SampleGroup = "sample"
+	CustomGroup = "custom"
+)
+
+// ReimportPolicy decides what happens to existing samples when a problem is imported again.
+type ReimportPolicy string
+
+const (
+	// ReimportOverwrite replaces all existing samples with the imported ones.
+	ReimportOverwrite ReimportPolicy = "overwrite"
+	// ReimportKeep leaves existing samples alone.
+	ReimportKeep ReimportPolicy = "keep"
+	// ReimportMerge adds the imported samples that are not present yet.
+	ReimportMerge ReimportPolicy = "merge"
+)
+
+func ParseReimportPolicy(s string) (ReimportPolicy, error) {
+	switch policy := ReimportPolicy(s); policy {
+	case ReimportOverwrite, ReimportKeep, ReimportMerge:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid re-import policy %q (expected overwrite, keep or merge)", s)
+	}
+}
+
+// TestsPath returns the directory holding the grouped tests of a problem.
+func (d *DirectoryManager) TestsPath(p Problem) string {
+	return filepath.Join(d.FullProblemPath(p), execution.TestsDir)
+}
+
+// WriteTestCases stores the samples of a problem as tests/sample-<prefix>N,
+// handling samples left by an earlier import according to policy.
+func (d *DirectoryManager) WriteTestCases(p Problem, testCases []execution.TestCase, inputPrefix, outputPrefix string, policy ReimportPolicy) error {
+	return d.writeTestCases(nil, p, testCases, inputPrefix, outputPrefix, policy)
+}
+
+func (d *DirectoryManager) writeTestCases(j *journal, p Problem, testCases []execution.TestCase, inputPrefix, outputPrefix string, policy ReimportPolicy) error {
+	dir := d.TestsPath(p)
+	if err := j.mkdirAll(dir); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	existing, err := d.readGroup(dir, SampleGroup, inputPrefix, outputPrefix)
+	if err != nil {
+		return err
+	}
+
+	if err := d.migrateLegacyTests(j, p, append(slices.Collect(maps.Values(existing)), testCases...), inputPrefix, outputPrefix); err != nil {
+		return err
+	}
+
+	next := 1
+	switch policy {
+	case ReimportKeep:
+		if len(existing) > 0 {
+			d.logger.Printf("Keeping %d existing sample(s) in %s", len(existing), dir)
+			return nil
+		}
+
+	case ReimportMerge:
+		seen := make(map[execution.TestCase]bool, len(existing))
+		for num, tc := range existing {
+			seen[normalizeTestCase(tc)] = true
+			next = max(next, num+1)
+		}
+		var added []execution.TestCase
+		for _, tc := range testCases {
+			if !seen[normalizeTestCase(tc)] {
+				seen[normalizeTestCase(tc)] = true
+				added = append(added, tc)
+			}
+		}
+		testCases = added
+
+	case ReimportOverwrite:
+		for num := range existing {
+			for _, prefix := range []string{inputPrefix, outputPrefix} {
+				path := filepath.Join(dir, testFileName(SampleGroup, prefix, num))
+				if err := j.remove(path); err != nil {
+					return fmt.Errorf("removing stale sample: %w", err)
+				}
+			}
+		}
+
+	default:
+		return fmt.Errorf("invalid re-import policy %q", policy)
+	}
+
 	for i, tc := range testCases {
-		inFile := filepath.Join(dir, fmt.Sprintf("%s%d", inputPrefix, i+1))
-		outFile := filepath.Join(dir, fmt.Sprintf("%s%d", outputPrefix, i+1))
+		inFile := filepath.Join(dir, testFileName(SampleGroup, inputPrefix, next+i))
+		outFile := filepath.Join(dir, testFileName(SampleGroup, outputPrefix, next+i))
 
-		if err := os.WriteFile(inFile, []byte(tc.Input), 0o644); err != nil {
+		if err := j.writeFile(inFile, []byte(tc.Input)); err != nil {
 			return fmt.Errorf("writing input file: %w", err)
 		}
-		if err := os.WriteFile(outFile, []byte(tc.Output), 0o644); err != nil {
+		if err := j.writeFile(outFile, []byte(tc.Output)); err != nil {
 			return fmt.Errorf("writing output file: %w", err)
 		}
 	}
 	return nil
 }
 
-func (d *DirectoryManager) WriteMetadata(p Problem, metadata any) error {
-	metaFile := filepath.Join(d.FullProblemPath(p), "problem.json")
-	file, err := os.Create(metaFile)
+// AddCustomTest stores tc as the next custom test of a problem,
+// tests/custom-<prefix>N, and returns N.
+func (d *DirectoryManager) AddCustomTest(p Problem, tc execution.TestCase, inputPrefix, outputPrefix string) (int, error) {
+	dir := d.TestsPath(p)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	existing, err := d.readGroup(dir, CustomGroup, inputPrefix, outputPrefix)
+	if err != nil {
+		return 0, err
+	}
+	num := 1
+	for n := range existing {
+		num = max(num, n+1)
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, testFileName(CustomGroup, inputPrefix, num)), []byte(tc.Input), 0o644); err != nil {
+		return 0, fmt.Errorf("writing input file: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, testFileName(CustomGroup, outputPrefix, num)), []byte(tc.Output), 0o644); err != nil {
+		return 0, fmt.Errorf("writing output file: %w", err)
+	}
+	return num, nil
+}
+
+// migrateLegacyTests moves the input<N>/output<N> files older versions
+// kept directly in the problem directory out of the way, as they would
+// otherwise run next to the tests in the tests directory. Pairs matching
+// one of samples are removed; the others were written by hand and become
+// custom tests.
+func (d *DirectoryManager) migrateLegacyTests(j *journal, p Problem, samples []execution.TestCase, inputPrefix, outputPrefix string) error {
+	dir := d.FullProblemPath(p)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading problem directory: %w", err)
+	}
+
+	type legacyTest struct {
+		tc    execution.TestCase
+		files []string
+	}
+	legacy := make(map[int]*legacyTest)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		for _, prefix := range []string{inputPrefix, outputPrefix} {
+			numStr, found := strings.CutPrefix(entry.Name(), prefix)
+			num, err := strconv.Atoi(numStr)
+			if !found || err != nil {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("reading legacy test: %w", err)
+			}
+			lt := legacy[num]
+			if lt == nil {
+				lt = &legacyTest{}
+				legacy[num] = lt
+			}
+			if prefix == inputPrefix {
+				lt.tc.Input = string(content)
+			} else {
+				lt.tc.Output = string(content)
+			}
+			lt.files = append(lt.files, entry.Name())
+			break
+		}
+	}
+	if len(legacy) == 0 {
+		return nil
+	}
+
+	known := make(map[execution.TestCase]bool, len(samples))
+	for _, tc := range samples {
+		known[normalizeTestCase(tc)] = true
+	}
+	testsDir := d.TestsPath(p)
+	custom, err := d.readGroup(testsDir, CustomGroup, inputPrefix, outputPrefix)
+	if err != nil {
+		return err
+	}
+	next := 1
+	for num := range custom {
+		next = max(next, num+1)
+	}
+
+	for _, num := range slices.Sorted(maps.Keys(legacy)) {
+		lt := legacy[num]
+		if known[normalizeTestCase(lt.tc)] {
+			d.logger.Printf("Removed legacy sample %s", strings.Join(lt.files, ", "))
+		} else {
+			inFile := filepath.Join(testsDir, testFileName(CustomGroup, inputPrefix, next))
+			outFile := filepath.Join(testsDir, testFileName(CustomGroup, outputPrefix, next))
+			if err := j.writeFile(inFile, []byte(lt.tc.Input)); err != nil {
+				return fmt.Errorf("writing input file: %w", err)
+			}
+			if err := j.writeFile(outFile, []byte(lt.tc.Output)); err != nil {
+				return fmt.Errorf("writing output file: %w", err)
+			}
+			d.logger.Printf("Moved legacy test %s to %s", strings.Join(lt.files, ", "), filepath.Base(inFile))
+			next++
+		}
+		for _, name := range lt.files {
+			if err := j.remove(filepath.Join(dir, name)); err != nil {
+				return fmt.Errorf("removing legacy test: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func testFileName(group, prefix string, num int) string {
+	return fmt.Sprintf("%s-%s%d", group, prefix, num)
+}
+
+func normalizeTestCase(tc execution.TestCase) execution.TestCase {
+	return execution.TestCase{Input: strings.TrimSpace(tc.Input), Output: strings.TrimSpace(tc.Output)}
+}
+
+// readGroup reads the tests of one group, keyed by their number.
+func (d *DirectoryManager) readGroup(dir, group, inputPrefix, outputPrefix string) (map[int]execution.TestCase, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("creating metadata file: %w", err)
+		return nil, fmt.Errorf("reading tests directory: %w", err)
 	}
-	defer file.Close()
 
-	enc := json.NewEncoder(file)
+	tests := make(map[int]execution.TestCase)
+	for _, entry := range entries {
+		name, found := strings.CutPrefix(entry.Name(), group+"-")
+		if !found || entry.IsDir() {
+			continue
+		}
+
+		isInput := strings.HasPrefix(name, inputPrefix)
+		numStr := strings.TrimPrefix(name, inputPrefix)
+		if !isInput {
+			if !strings.HasPrefix(name, outputPrefix) {
+				continue
+			}
+			numStr = strings.TrimPrefix(name, outputPrefix)
+		}
+		num, err := strconv.Atoi(numStr)
+		if err != nil {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading test file: %w", err)
+		}
+		tc := tests[num]
+		if isInput {
+			tc.Input = string(content)
+		} else {
+			tc.Output = string(content)
+		}
+		tests[num] = tc
+	}
+	return tests, nil
+}
+
+func (d *DirectoryManager) WriteMetadata(p Problem, metadata any) error {
+	return d.writeMetadata(nil, p, metadata)
+}
+
+func (d *DirectoryManager) writeMetadata(j *journal, p Problem, metadata any) error {
+	metaFile := filepath.Join(d.FullProblemPath(p), MetadataFile)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
 	enc.SetIndent("", "  ")
-	return enc.Encode(metadata)
+	if err := enc.Encode(metadata); err != nil {
+		return fmt.Errorf("encoding metadata: %w", err)
+	}
+
+	if err := j.writeFile(metaFile, buf.Bytes()); err != nil {
+		return fmt.Errorf("writing metadata file: %w", err)
+	}
+	return nil
 }
 
 func (d *DirectoryManager) WriteProgramFile(p Problem, filename, templateContent string) error {
+	return d.writeProgramFile(nil, p, filename, templateContent)
+}
+
+func (d *DirectoryManager) writeProgramFile(j *journal, p Problem, filename, templateContent string) error {
 	path := filepath.Join(d.FullProblemPath(p), filename)
 	if _, err := os.Stat(path); err == nil {
 		d.logger.Printf("Program file already exists: %s", path)
 		return nil
 	}
-	return os.WriteFile(path, []byte(templateContent), 0o644)
+	return j.writeFile(path, []byte(templateContent))
 }
 
 func (d *DirectoryManager) LoadTemplate(templatePath string) (string, error) {
@@ -99,6 +517,44 @@ func (d *DirectoryManager) LoadTemplate(templatePath string) (string, error) {
 	return string(content), nil
 }
 
+// ReadMetadata reads the problem.json of a problem.
+func (d *DirectoryManager) ReadMetadata(p Problem) (*Metadata, error) {
+	return readMetadataFile(filepath.Join(d.FullProblemPath(p), MetadataFile))
+}
+
+// UpdateMetadata reads the problem.json of a problem, applies update to it
+// and writes it back.
+func (d *DirectoryManager) UpdateMetadata(p Problem, update func(*Metadata)) error {
+	meta, err := d.ReadMetadata(p)
+	if err != nil {
+		return err
+	}
+	update(meta)
+	return d.WriteMetadata(p, meta)
+}
+
+func readMetadataFile(path string) (*Metadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading metadata file: %w", err)
+	}
+
+	var meta Metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("decoding metadata file %s: %w", path, err)
+	}
+	return &meta, nil
+}
+
+// problemFromMetadata recovers the directory key of a problem from its metadata.
+func (d *DirectoryManager) problemFromMetadata(meta *Metadata) (Problem, error) {
+	parsed, err := ccparser.NewParser(d.logger).Parse(&meta.CCProblem)
+	if err != nil {
+		return Problem{}, err
+	}
+	return NewProblem(parsed, meta.ImportedAt), nil
+}
+
 func (d *DirectoryManager) GetCurrentProblemKey() (Problem, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
@@ -109,7 +565,27 @@ func (d *DirectoryManager) GetCurrentProblemKey() (Problem, error) {
 		}, err // Return empty problem on error
 	}
 
-	parts := strings.Split(currentDir, string(filepath.Separator))
+	return d.ProblemKeyForDir(currentDir)
+}
+
+// ProblemKeyForDir returns the problem that dir (or one of its parents below
+// root) belongs to. The problem.json of the problem directory is the source of
+// truth; without one the legacy <contest>/<problemCode> structure is assumed.
+func (d *DirectoryManager) ProblemKeyForDir(dir string) (Problem, error) {
+	for cur := filepath.Clean(dir); ; cur = filepath.Dir(cur) {
+		meta, err := readMetadataFile(filepath.Join(cur, MetadataFile))
+		if err == nil {
+			return d.problemFromMetadata(meta)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			d.logger.Printf("WARN: ignoring unreadable metadata in %s: %s", cur, err)
+		}
+		if cur == d.rootPath || filepath.Dir(cur) == cur {
+			break
+		}
+	}
+
+	parts := strings.Split(filepath.Clean(dir), string(filepath.Separator))
 
 	if len(parts) < 2 {
 		d.logger.Println("current working dir path segments less than two")
@@ -136,3 +612,165 @@ func (d *DirectoryManager) GetCurrentProblemKey() (Problem, error) {
 		ProblemCode: problemCode,
 	}, nil
 }
+
+// OverrideFiles returns the override files that apply to dir, from root down
+// to dir itself, so that later files take precedence. Directories outside
+// root have no overrides.
+func (d *DirectoryManager) OverrideFiles(dir string) []string {
+	rel, err := filepath.Rel(d.rootPath, filepath.Clean(dir))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	dirs := []string{d.rootPath}
+	if rel != "." {
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			dirs = append(dirs, filepath.Join(dirs[len(dirs)-1], part))
+		}
+	}
+
+	var files []string
+	for _, cur := range dirs {
+		path := filepath.Join(cur, OverrideFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// ProblemEntry is a problem found on disk below root.
+type ProblemEntry struct {
+	Problem  Problem
+	Dir      string
+	Metadata *Metadata
+}
+
+// ListProblems finds every problem below root by looking for problem.json
+// files, independently of the layout that placed them there.
+func (d *DirectoryManager) ListProblems() ([]ProblemEntry, error) {
+	var entries []ProblemEntry
+
+	err := filepath.WalkDir(d.rootPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == d.rootPath && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+
+		metaPath := filepath.Join(path, MetadataFile)
+		if _, err := os.Stat(metaPath); err != nil {
+			return nil
+		}
+
+		meta, err := readMetadataFile(metaPath)
+		if err != nil {
+			d.logger.Printf("WARN: skipping %s: %s", path, err)
+			return fs.SkipDir
+		}
+		p, err := d.problemFromMetadata(meta)
+		if err != nil {
+			d.logger.Printf("WARN: skipping %s: %s", path, err)
+			return fs.SkipDir
+		}
+
+		entries = append(entries, ProblemEntry{Problem: p, Dir: path, Metadata: meta})
+		return fs.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing problems: %w", err)
+	}
+	return entries, nil
+}
+
+// FindProblem looks below root for an imported problem that is p apart from
+// the year, which layouts using {{.Year}} take from the first import, and
+// returns it with its own year.
+func (d *DirectoryManager) FindProblem(p Problem) (Problem, bool, error) {
+	problems, err := d.ListProblems()
+	if err != nil {
+		return Problem{}, false, err
+	}
+	key := p
+	key.Year = 0
+	for _, entry := range problems {
+		found := entry.Problem
+		found.Year = 0
+		if found == key {
+			return entry.Problem, true, nil
+		}
+	}
+	return Problem{}, false, nil
+}
+
+// Move describes a problem directory relocated by MigrateLayout.
+type Move struct {
+	From string
+	To   string
+	Err  error
+}
+
+// MigrateLayout moves every problem below root to where layout places it and
+// makes layout the active one. With dryRun set nothing is touched on disk
+// and the active layout is kept.
+// Problems that cannot be moved are reported through Move.Err; the others
+// are still migrated.
+func (d *DirectoryManager) MigrateLayout(layout string, dryRun bool) ([]Move, error) {
+	target, err := parseLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+
+	problems, err := d.ListProblems()
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []Move
+	for _, entry := range problems {
+		rel, err := renderLayout(target, entry.Problem)
+		if err != nil {
+			moves = append(moves, Move{From: entry.Dir, Err: err})
+			continue
+		}
+
+		move := Move{From: entry.Dir, To: filepath.Join(d.rootPath, rel)}
+		if move.From == move.To {
+			continue
+		}
+		if !dryRun {
+			move.Err = d.moveDir(move.From, move.To)
+		}
+		moves = append(moves, move)
+	}
+
+	if !dryRun {
+		d.layout = target
+	}
+	return moves, nil
+}
+
+func (d *DirectoryManager) moveDir(from, to string) error {
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("destination already exists: %s", to)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("moving problem directory: %w", err)
+	}
+	d.logger.Printf("Moved %s -> %s", from, to)
+
+	// Drop the parents the old layout left empty, e.g. an emptied contest directory.
+	for dir := filepath.Dir(from); dir != d.rootPath && strings.HasPrefix(dir, d.rootPath); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+	return nil
+}
@@ -7,7 +7,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/ccparser"
 	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
 )
 
@@ -31,6 +33,28 @@ func TestFullProblemPath(t *testing.T) {
 	}
 }
 
+func TestFullProblemPath_CustomLayout(t *testing.T) {
+	dm, root := setupTestManager(t)
+	if err := dm.SetLayout("{{.Judge}}/{{.Year}}/{{.Contest}}-{{.Index}}"); err != nil {
+		t.Fatalf("SetLayout failed: %v", err)
+	}
+
+	p := Problem{ContestCode: 1985, ProblemCode: "B_Long_Name", Year: 2024}
+	expected := filepath.Join(root, "codeforces", "2024", "1985-B")
+	if path := dm.FullProblemPath(p); path != expected {
+		t.Errorf("expected path %q, got %q", expected, path)
+	}
+}
+
+func TestSetLayout_Invalid(t *testing.T) {
+	dm, _ := setupTestManager(t)
+	for _, layout := range []string{"{{.Contest", "{{.Unknown}}", "../{{.Contest}}", ""} {
+		if err := dm.SetLayout(layout); err == nil {
+			t.Errorf("expected error for layout %q", layout)
+		}
+	}
+}
+
 func TestEnsureDir(t *testing.T) {
 	dm, _ := setupTestManager(t)
 	p := sampleProblem()
@@ -55,20 +79,143 @@ func TestWriteTestCases(t *testing.T) {
 		{Input: "1 2", Output: "3"},
 		{Input: "4 5", Output: "9"},
 	}
-	err = dm.WriteTestCases(p, testCases, "input", "output")
+	err = dm.WriteTestCases(p, testCases, "input", "output", ReimportOverwrite)
 	if err != nil {
 		t.Fatalf("WriteTestCases failed: %v", err)
 	}
 
 	for i := range testCases {
-		inFile := filepath.Join(dm.FullProblemPath(p), fmt.Sprintf("input%d", i+1))
-		outFile := filepath.Join(dm.FullProblemPath(p), fmt.Sprintf("output%d", i+1))
+		inFile := filepath.Join(dm.TestsPath(p), fmt.Sprintf("sample-input%d", i+1))
+		outFile := filepath.Join(dm.TestsPath(p), fmt.Sprintf("sample-output%d", i+1))
 
 		checkFileContains(t, inFile, testCases[i].Input)
 		checkFileContains(t, outFile, testCases[i].Output)
 	}
 }
 
+func TestAddCustomTest(t *testing.T) {
+	dm, _ := setupTestManager(t)
+	p := sampleProblem()
+
+	for want := 1; want <= 2; want++ {
+		num, err := dm.AddCustomTest(p, execution.TestCase{Input: fmt.Sprint(want), Output: "ok"}, "input", "output")
+		if err != nil {
+			t.Fatalf("AddCustomTest failed: %v", err)
+		}
+		if num != want {
+			t.Errorf("AddCustomTest = %d, want %d", num, want)
+		}
+	}
+	checkFileContains(t, filepath.Join(dm.TestsPath(p), "custom-input2"), "2")
+	checkFileContains(t, filepath.Join(dm.TestsPath(p), "custom-output2"), "ok")
+}
+
+func TestWriteTestCases_LegacySamples(t *testing.T) {
+	sample := execution.TestCase{Input: "1 2", Output: "3"}
+	legacy := map[string]string{
+		"input1":          "1 2\n",
+		"output1":         "3\n",
+		"input3":          "100 200",
+		"output3":         "300",
+		"input_notes.txt": "old",
+	}
+
+	for _, policy := range []ReimportPolicy{ReimportOverwrite, ReimportKeep, ReimportMerge} {
+		t.Run(string(policy), func(t *testing.T) {
+			dm, _ := setupTestManager(t)
+			p := sampleProblem()
+			dir, err := dm.EnsureDir(p)
+			if err != nil {
+				t.Fatalf("EnsureDir failed: %v", err)
+			}
+			for name, content := range legacy {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatalf("writing %s failed: %v", name, err)
+				}
+			}
+
+			if err := dm.WriteTestCases(p, []execution.TestCase{sample}, "input", "output", policy); err != nil {
+				t.Fatalf("WriteTestCases failed: %v", err)
+			}
+			for _, name := range []string{"input1", "output1", "input3", "output3"} {
+				if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+					t.Errorf("expected legacy test %s to be moved out of the problem directory", name)
+				}
+			}
+			checkFileContains(t, filepath.Join(dir, "input_notes.txt"), "old")
+			checkFileContains(t, filepath.Join(dm.TestsPath(p), "sample-input1"), "1 2")
+			checkFileContains(t, filepath.Join(dm.TestsPath(p), "custom-input1"), "100 200")
+			checkFileContains(t, filepath.Join(dm.TestsPath(p), "custom-output1"), "300")
+			if _, err := os.Stat(filepath.Join(dm.TestsPath(p), "custom-input2")); !os.IsNotExist(err) {
+				t.Errorf("expected the legacy sample not to become a custom test")
+			}
+		})
+	}
+}
+
+func TestWriteTestCases_ReimportPolicies(t *testing.T) {
+	first := []execution.TestCase{
+		{Input: "1 2", Output: "3"},
+		{Input: "4 5", Output: "9"},
+		{Input: "6 7", Output: "13"},
+	}
+	second := []execution.TestCase{
+		{Input: "1 2", Output: "3"},
+		{Input: "10 20", Output: "30"},
+	}
+
+	tests := []struct {
+		policy   ReimportPolicy
+		expected map[int]string // sample number -> input, 0 entries must be absent
+	}{
+		{ReimportOverwrite, map[int]string{1: "1 2", 2: "10 20", 3: ""}},
+		{ReimportKeep, map[int]string{1: "1 2", 2: "4 5", 3: "6 7", 4: ""}},
+		{ReimportMerge, map[int]string{1: "1 2", 2: "4 5", 3: "6 7", 4: "10 20", 5: ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			dm, _ := setupTestManager(t)
+			p := sampleProblem()
+			if _, err := dm.EnsureDir(p); err != nil {
+				t.Fatalf("EnsureDir failed: %v", err)
+			}
+
+			custom := filepath.Join(dm.TestsPath(p), "custom-input1")
+			if err := dm.WriteTestCases(p, first, "input", "output", ReimportOverwrite); err != nil {
+				t.Fatalf("first import failed: %v", err)
+			}
+			if err := os.WriteFile(custom, []byte("42"), 0o644); err != nil {
+				t.Fatalf("writing custom test failed: %v", err)
+			}
+			if err := dm.WriteTestCases(p, second, "input", "output", tt.policy); err != nil {
+				t.Fatalf("re-import failed: %v", err)
+			}
+
+			for num, input := range tt.expected {
+				path := filepath.Join(dm.TestsPath(p), fmt.Sprintf("sample-input%d", num))
+				if input == "" {
+					if _, err := os.Stat(path); !os.IsNotExist(err) {
+						t.Errorf("expected %s to be absent", path)
+					}
+					continue
+				}
+				checkFileContains(t, path, input)
+			}
+			checkFileContains(t, custom, "42")
+		})
+	}
+}
+
+func TestParseReimportPolicy(t *testing.T) {
+	if _, err := ParseReimportPolicy("merge"); err != nil {
+		t.Errorf("expected merge to be valid: %v", err)
+	}
+	if _, err := ParseReimportPolicy("append"); err == nil {
+		t.Errorf("expected error for unknown policy")
+	}
+}
+
 func TestWriteMetadata(t *testing.T) {
 	dm, _ := setupTestManager(t)
 	p := sampleProblem()
@@ -128,6 +275,175 @@ func TestWriteProgramFile(t *testing.T) {
 	}
 }
 
+func writeImportedProblem(t *testing.T, dm *DirectoryManager, url, name string) Problem {
+	t.Helper()
+	meta := Metadata{
+		CCProblem:  ccparser.CCProblem{Name: name, URL: url},
+		ImportedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	parsed, err := ccparser.NewParser(dm.logger).Parse(&meta.CCProblem)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	p := NewProblem(parsed, meta.ImportedAt)
+	if _, err := dm.EnsureDir(p); err != nil {
+		t.Fatalf("EnsureDir failed: %v", err)
+	}
+	if err := dm.WriteMetadata(p, meta); err != nil {
+		t.Fatalf("WriteMetadata failed: %v", err)
+	}
+	return p
+}
+
+func TestUpdateMetadata(t *testing.T) {
+	dm, _ := setupTestManager(t)
+	p := writeImportedProblem(t, dm, "https://codeforces.com/contest/1985/problem/C", "C. Good Prefixes")
+
+	record := SubmissionRecord{ID: 265000000, Verdict: "OK", PassedTests: 12, TimeMs: 46}
+	err := dm.UpdateMetadata(p, func(meta *Metadata) {
+		meta.Submissions = append(meta.Submissions, record)
+	})
+	if err != nil {
+		t.Fatalf("UpdateMetadata failed: %v", err)
+	}
+
+	meta, err := dm.ReadMetadata(p)
+	if err != nil {
+		t.Fatalf("ReadMetadata failed: %v", err)
+	}
+	if meta.Name != "C. Good Prefixes" || len(meta.Submissions) != 1 || meta.Submissions[0] != record {
+		t.Errorf("unexpected metadata after update: %+v", meta)
+	}
+}
+
+func TestProblemKeyForDir(t *testing.T) {
+	dm, _ := setupTestManager(t)
+	if err := dm.SetLayout("{{.Contest}}-{{.Index}}"); err != nil {
+		t.Fatalf("SetLayout failed: %v", err)
+	}
+	p := writeImportedProblem(t, dm, "https://codeforces.com/contest/1985/problem/C", "C. Good Prefixes")
+
+	nested := filepath.Join(dm.FullProblemPath(p), "tests")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	got, err := dm.ProblemKeyForDir(nested)
+	if err != nil {
+		t.Fatalf("ProblemKeyForDir failed: %v", err)
+	}
+	if got != p {
+		t.Errorf("expected %+v, got %+v", p, got)
+	}
+}
+
+func TestProblemKeyForDir_Legacy(t *testing.T) {
+	dm, root := setupTestManager(t)
+	got, err := dm.ProblemKeyForDir(filepath.Join(root, "1234", "A_Test"))
+	if err != nil {
+		t.Fatalf("ProblemKeyForDir failed: %v", err)
+	}
+	if got.ContestCode != 1234 || got.ProblemCode != "A_Test" {
+		t.Errorf("unexpected problem key: %+v", got)
+	}
+}
+
+func TestMigrateLayout(t *testing.T) {
+	dm, root := setupTestManager(t)
+	a := writeImportedProblem(t, dm, "https://codeforces.com/contest/1985/problem/A", "A. Circle")
+	b := writeImportedProblem(t, dm, "https://codeforces.com/contest/1985/problem/B", "B. Square")
+
+	moves, err := dm.MigrateLayout("{{.Judge}}/{{.Contest}}/{{.Index}}", false)
+	if err != nil {
+		t.Fatalf("MigrateLayout failed: %v", err)
+	}
+	if len(moves) != 2 {
+		t.Fatalf("expected 2 moves, got %d", len(moves))
+	}
+	for _, move := range moves {
+		if move.Err != nil {
+			t.Errorf("move %s failed: %v", move.From, move.Err)
+		}
+	}
+
+	for _, p := range []Problem{a, b} {
+		expected := filepath.Join(root, "codeforces", "1985", p.Index)
+		if path := dm.FullProblemPath(p); path != expected {
+			t.Errorf("expected path %q, got %q", expected, path)
+		}
+		if _, err := os.Stat(filepath.Join(expected, MetadataFile)); err != nil {
+			t.Errorf("problem not moved to %s: %v", expected, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "1985")); !os.IsNotExist(err) {
+		t.Errorf("expected emptied contest directory to be removed")
+	}
+
+	moves, err = dm.MigrateLayout("{{.Judge}}/{{.Contest}}/{{.Index}}", false)
+	if err != nil {
+		t.Fatalf("second MigrateLayout failed: %v", err)
+	}
+	if len(moves) != 0 {
+		t.Errorf("expected no moves when already migrated, got %d", len(moves))
+	}
+}
+
+func TestMigrateLayout_DryRun(t *testing.T) {
+	dm, _ := setupTestManager(t)
+	p := writeImportedProblem(t, dm, "https://codeforces.com/contest/1985/problem/A", "A. Circle")
+	before := dm.FullProblemPath(p)
+
+	moves, err := dm.MigrateLayout("{{.Contest}}{{.Index}}", true)
+	if err != nil {
+		t.Fatalf("MigrateLayout failed: %v", err)
+	}
+	if len(moves) != 1 {
+		t.Fatalf("expected 1 move, got %d", len(moves))
+	}
+	if _, err := os.Stat(before); err != nil {
+		t.Errorf("dry run should not move %s: %v", before, err)
+	}
+	if got := dm.FullProblemPath(p); got != before {
+		t.Errorf("dry run should keep the layout: expected %s, got %s", before, got)
+	}
+}
+
+func TestOverrideFiles(t *testing.T) {
+	dm, root := setupTestManager(t)
+	problemDir := filepath.Join(root, "1985", "A")
+	if err := os.MkdirAll(problemDir, 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(root, OverrideFile),
+		filepath.Join(problemDir, OverrideFile),
+	}
+	for _, path := range expected {
+		if err := os.WriteFile(path, []byte("language: py\n"), 0o644); err != nil {
+			t.Fatalf("writing override failed: %v", err)
+		}
+	}
+
+	files := dm.OverrideFiles(problemDir)
+	if len(files) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, files)
+		}
+	}
+
+	if files := dm.OverrideFiles(root); len(files) != 1 {
+		t.Errorf("expected only the root override for root itself, got %v", files)
+	}
+	if files := dm.OverrideFiles(t.TempDir()); len(files) != 0 {
+		t.Errorf("expected no overrides outside root, got %v", files)
+	}
+}
+
 func checkFileContains(t *testing.T, path, expected string) {
 	t.Helper()
 	data, err := os.ReadFile(path)
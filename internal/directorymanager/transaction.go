@@ -0,0 +1,209 @@
+package directorymanager
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+)
+
+// Stages of an import, as reported by ImportError.
+const (
+	StageDirectory = "directory"
+	StageTests     = "tests"
+	StageProgram   = "program"
+	StageMetadata  = "metadata"
+)
+
+// ImportRequest is everything written to disk when a problem is imported.
+type ImportRequest struct {
+	TestCases    []execution.TestCase
+	InputPrefix  string
+	OutputPrefix string
+	Policy       ReimportPolicy
+	ProgramFile  string
+	Template     string
+	Metadata     any
+}
+
+// ImportError reports the stage at which an import failed. The problem
+// directory has already been rolled back when it is returned.
+type ImportError struct {
+	Stage string
+	Err   error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("import failed at %s stage: %v", e.Stage, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// Import writes the tests, program file and metadata of a problem as a single
+// transaction: if any step fails, every change made so far is undone and the
+// problem directory is left as it was before.
+func (d *DirectoryManager) Import(p Problem, req ImportRequest) error {
+	j := &journal{}
+
+	fail := func(stage string, err error) error {
+		if rbErr := j.rollback(); rbErr != nil {
+			d.logger.Printf("ERROR: failed to roll back import of %s: %s", d.FullProblemPath(p), rbErr)
+			err = errors.Join(err, fmt.Errorf("rollback: %w", rbErr))
+		}
+		return &ImportError{Stage: stage, Err: err}
+	}
+
+	if _, err := d.ensureDir(j, p); err != nil {
+		return fail(StageDirectory, err)
+	}
+	if err := d.writeTestCases(j, p, req.TestCases, req.InputPrefix, req.OutputPrefix, req.Policy); err != nil {
+		return fail(StageTests, err)
+	}
+	if err := d.writeProgramFile(j, p, req.ProgramFile, req.Template); err != nil {
+		return fail(StageProgram, err)
+	}
+	if err := d.writeMetadata(j, p, req.Metadata); err != nil {
+		return fail(StageMetadata, err)
+	}
+	return nil
+}
+
+// journal records the original state of everything an import touches so it
+// can be rolled back. A nil journal performs the same operations without
+// recording anything.
+type journal struct {
+	dirs    []string
+	files   []fileBackup
+	touched map[string]bool
+}
+
+type fileBackup struct {
+	path    string
+	existed bool
+	content []byte
+	mode    fs.FileMode
+}
+
+func (j *journal) backup(path string) error {
+	if j == nil || j.touched[path] {
+		return nil
+	}
+
+	b := fileBackup{path: path}
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("backing up %s: %w", path, err)
+		}
+		b.existed, b.content, b.mode = true, content, info.Mode().Perm()
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("backing up %s: %w", path, err)
+	}
+
+	if j.touched == nil {
+		j.touched = make(map[string]bool)
+	}
+	j.touched[path] = true
+	j.files = append(j.files, b)
+	return nil
+}
+
+func (j *journal) writeFile(path string, data []byte) error {
+	if err := j.backup(path); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0o644)
+}
+
+func (j *journal) remove(path string) error {
+	if err := j.backup(path); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (j *journal) mkdirAll(dir string) error {
+	var missing []string
+	if j != nil {
+		for cur := dir; ; cur = filepath.Dir(cur) {
+			if _, err := os.Stat(cur); err == nil || filepath.Dir(cur) == cur {
+				break
+			}
+			missing = append([]string{cur}, missing...)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if j != nil {
+		j.dirs = append(j.dirs, missing...)
+	}
+	return nil
+}
+
+// rollback restores every touched file, most recent first, and removes the
+// directories that did not exist before.
+func (j *journal) rollback() error {
+	var errs []error
+
+	for i := len(j.files) - 1; i >= 0; i-- {
+		b := j.files[i]
+		if b.existed {
+			if err := writeFileAtomic(b.path, b.content, b.mode); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := os.Remove(b.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	for i := len(j.dirs) - 1; i >= 0; i-- {
+		if err := os.Remove(j.dirs[i]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
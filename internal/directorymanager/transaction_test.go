@@ -0,0 +1,116 @@
+package directorymanager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+)
+
+func sampleImportRequest() ImportRequest {
+	return ImportRequest{
+		TestCases:    []execution.TestCase{{Input: "1 2", Output: "3"}},
+		InputPrefix:  "input",
+		OutputPrefix: "output",
+		Policy:       ReimportOverwrite,
+		ProgramFile:  "main.cpp",
+		Template:     "// template",
+		Metadata:     map[string]string{"name": "A. Test"},
+	}
+}
+
+func TestImport(t *testing.T) {
+	dm, _ := setupTestManager(t)
+	p := sampleProblem()
+
+	if err := dm.Import(p, sampleImportRequest()); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	dir := dm.FullProblemPath(p)
+	checkFileContains(t, filepath.Join(dm.TestsPath(p), "sample-input1"), "1 2")
+	checkFileContains(t, filepath.Join(dir, "main.cpp"), "// template")
+	if _, err := os.Stat(filepath.Join(dir, MetadataFile)); err != nil {
+		t.Errorf("metadata not written: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("reading problem dir failed: %v", err)
+	}
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".cpp" && entry.Name() != MetadataFile && entry.Name() != execution.TestsDir {
+			t.Errorf("unexpected file left behind: %s", entry.Name())
+		}
+	}
+}
+
+func TestImport_RollbackNewProblem(t *testing.T) {
+	dm, root := setupTestManager(t)
+	p := sampleProblem()
+
+	req := sampleImportRequest()
+	req.Metadata = map[string]any{"bad": make(chan int)} // cannot be encoded
+
+	err := dm.Import(p, req)
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("expected ImportError, got %v", err)
+	}
+	if importErr.Stage != StageMetadata {
+		t.Errorf("expected stage %q, got %q", StageMetadata, importErr.Stage)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "1234")); !os.IsNotExist(err) {
+		t.Errorf("expected problem directory to be rolled back, stat error: %v", err)
+	}
+}
+
+func TestImport_RollbackExistingProblem(t *testing.T) {
+	dm, _ := setupTestManager(t)
+	p := sampleProblem()
+
+	first := sampleImportRequest()
+	first.TestCases = []execution.TestCase{{Input: "a", Output: "1"}, {Input: "b", Output: "2"}}
+	if err := dm.Import(p, first); err != nil {
+		t.Fatalf("first Import failed: %v", err)
+	}
+	custom := filepath.Join(dm.TestsPath(p), "custom-input1")
+	if err := os.WriteFile(custom, []byte("mine"), 0o644); err != nil {
+		t.Fatalf("writing custom test failed: %v", err)
+	}
+
+	second := sampleImportRequest()
+	second.Metadata = map[string]any{"bad": make(chan int)}
+	if err := dm.Import(p, second); err == nil {
+		t.Fatal("expected second Import to fail")
+	}
+
+	checkFileContains(t, filepath.Join(dm.TestsPath(p), "sample-input1"), "a")
+	checkFileContains(t, filepath.Join(dm.TestsPath(p), "sample-input2"), "b")
+	checkFileContains(t, custom, "mine")
+	checkFileContains(t, filepath.Join(dm.FullProblemPath(p), "main.cpp"), "// template")
+	if _, err := os.Stat(filepath.Join(dm.FullProblemPath(p), MetadataFile)); err != nil {
+		t.Errorf("original metadata should be kept: %v", err)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file")
+
+	if err := writeFileAtomic(path, []byte("first"), 0o644); err != nil {
+		t.Fatalf("writeFileAtomic failed: %v", err)
+	}
+	if err := writeFileAtomic(path, []byte("second"), 0o600); err != nil {
+		t.Fatalf("writeFileAtomic failed: %v", err)
+	}
+	checkFileContains(t, path, "second")
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the target file, found %d entries", len(entries))
+	}
+}
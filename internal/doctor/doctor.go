@@ -0,0 +1,295 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/editor"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/lint"
+)
+
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+// Check is the outcome of one validation, with a hint on how to fix it.
+type Check struct {
+	Name    string
+	Status  Status
+	Message string
+	Hint    string
+}
+
+// Settings are the configuration values being checked.
+type Settings struct {
+	Root            string
+	Language        string
+	ProgramFile     string
+	BuildCommand    string
+	ExecuteCommand  string
+	InputPrefix     string
+	OutputPrefix    string
+	Port            string
+	Layout          string
+	ReimportPolicy  string
+	TemplatePath    string
+	EditorMode      string
+	EditorCommand   string
+	TerminalCommand string
+	EditorServer    string
+	RemoteCommand   string
+	Checker         string
+	TimeLimit       int // milliseconds, 0 for no limit
+	IncludePaths    []string
+	LibraryDir      string
+	LintRules       []string
+}
+
+// Failed reports whether any check failed.
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the configuration values themselves. It is cheap and does
+// not look at the environment, so it runs before every command.
+func Validate(s Settings) []Check {
+	checks := []Check{
+		required("root", s.Root),
+		required("language", s.Language),
+		required("programFile", s.ProgramFile),
+		commandTemplate("buildCommand", s.BuildCommand, false),
+		commandTemplate("executeCommand", s.ExecuteCommand, true),
+		prefixes(s.InputPrefix, s.OutputPrefix),
+		port(s.Port),
+	}
+
+	layout := Check{Name: "layout", Message: s.Layout}
+	if err := directorymanager.ValidateLayout(s.Layout); err != nil {
+		layout.Status, layout.Message = Fail, err.Error()
+		layout.Hint = "use fields such as {{.Contest}}/{{.Index}}; see migrate-layout --help"
+	}
+	checks = append(checks, layout)
+
+	policy := Check{Name: "reimportPolicy", Message: s.ReimportPolicy}
+	if _, err := directorymanager.ParseReimportPolicy(s.ReimportPolicy); err != nil {
+		policy.Status, policy.Message, policy.Hint = Fail, err.Error(), "set it to overwrite, keep or merge"
+	}
+	checks = append(checks, policy)
+
+	checker := Check{Name: "checker", Message: s.Checker}
+	if _, err := execution.ParseChecker(s.Checker); err != nil {
+		checker.Status, checker.Message, checker.Hint = Fail, err.Error(), "use exact, tokens or float:1e-6"
+	}
+	checks = append(checks, checker)
+
+	lintRules := Check{Name: "lintRules", Message: "all rules"}
+	if len(s.LintRules) > 0 {
+		lintRules.Message = strings.Join(s.LintRules, ", ")
+	}
+	if _, err := lint.ParseRules(s.LintRules); err != nil {
+		lintRules.Status, lintRules.Message, lintRules.Hint = Fail, err.Error(), "see 'codeforces-cli check --help' for the rules"
+	}
+	checks = append(checks, lintRules)
+
+	timeLimit := Check{Name: "timeLimit", Message: "no limit"}
+	if s.TimeLimit > 0 {
+		timeLimit.Message = fmt.Sprintf("%d ms", s.TimeLimit)
+	} else if s.TimeLimit < 0 {
+		timeLimit.Status, timeLimit.Message, timeLimit.Hint = Fail, fmt.Sprintf("negative time limit %d", s.TimeLimit), "set it in milliseconds, or 0 for no limit"
+	}
+	checks = append(checks, timeLimit)
+
+	ed := Check{Name: "editor", Message: s.EditorMode}
+	if _, err := editor.NewLauncher(editorConfig(s), nil); err != nil {
+		ed.Status, ed.Message = Fail, err.Error()
+		ed.Hint = "check editorMode, editorCommand, terminalCommand, editorServer and remoteCommand"
+	}
+	checks = append(checks, ed)
+
+	return checks
+}
+
+// Diagnose runs Validate plus the checks against the environment: tools on
+// PATH, a writable root, a readable template and a free port.
+func Diagnose(s Settings) []Check {
+	checks := Validate(s)
+	checks = append(checks,
+		writableDir("root writable", s.Root),
+		readableFile("templatePath", s.TemplatePath),
+		portFree(s.Port),
+	)
+	for _, dir := range s.IncludePaths {
+		checks = append(checks, libraryDir("includePaths "+dir, dir))
+	}
+	if s.LibraryDir != "" {
+		checks = append(checks, libraryDir("libraryDir", s.LibraryDir))
+	}
+
+	vars := map[string]string{"Path": "main", "Dir": "."}
+	checks = append(checks,
+		executable("buildCommand tool", s.BuildCommand, vars),
+		executable("executeCommand tool", s.ExecuteCommand, vars),
+	)
+
+	if args, err := mustLauncher(s).Command(editor.Vars{Path: "main", Dir: "."}); err == nil && len(args) > 0 {
+		checks = append(checks, lookPath("editor tool", args[0]))
+	}
+
+	return checks
+}
+
+func editorConfig(s Settings) editor.Config {
+	return editor.Config{
+		Mode:            editor.Mode(s.EditorMode),
+		Command:         s.EditorCommand,
+		TerminalCommand: s.TerminalCommand,
+		Server:          s.EditorServer,
+		RemoteCommand:   s.RemoteCommand,
+	}
+}
+
+// mustLauncher returns the editor launcher, or one that opens nothing when
+// the editor settings are invalid (Validate already reported them).
+func mustLauncher(s Settings) *editor.Launcher {
+	l, err := editor.NewLauncher(editorConfig(s), nil)
+	if err != nil {
+		l, _ = editor.NewLauncher(editor.Config{Mode: editor.ModeForeground}, nil)
+	}
+	return l
+}
+
+func required(name, value string) Check {
+	if strings.TrimSpace(value) == "" {
+		return Check{Name: name, Status: Fail, Message: "not set", Hint: fmt.Sprintf("set %s in the config file", name)}
+	}
+	return Check{Name: name, Message: value}
+}
+
+func commandTemplate(name, value string, needed bool) Check {
+	if strings.TrimSpace(value) == "" {
+		if needed {
+			return Check{Name: name, Status: Fail, Message: "not set", Hint: fmt.Sprintf(`set %s, e.g. "python3 {{.Path}}"`, name)}
+		}
+		return Check{Name: name, Message: "not set"}
+	}
+
+	if _, err := renderCommand(value, map[string]string{"Path": "main", "Dir": "."}); err != nil {
+		return Check{Name: name, Status: Fail, Message: err.Error(), Hint: "only {{.Path}} and {{.Dir}} are available"}
+	}
+	return Check{Name: name, Message: value}
+}
+
+// renderCommand renders a command template, failing on fields other than the ones in vars.
+func renderCommand(command string, vars map[string]string) (string, error) {
+	t, err := template.New("command").Option("missingkey=error").Parse(command)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func prefixes(input, output string) Check {
+	c := Check{Name: "test case prefixes", Message: fmt.Sprintf("%q / %q", input, output)}
+	switch {
+	case input == "" || output == "":
+		c.Status, c.Hint = Fail, "set testCaseInputPrefix and testCaseOutputPrefix"
+	case strings.HasPrefix(input, output) || strings.HasPrefix(output, input):
+		c.Status, c.Hint = Fail, "neither prefix may start with the other, e.g. use input and output"
+	}
+	return c
+}
+
+func port(value string) Check {
+	if p, err := strconv.Atoi(value); err != nil || p < 1 || p > 65535 {
+		return Check{Name: "port", Status: Fail, Message: fmt.Sprintf("%q is not a valid port", value), Hint: "Competitive Companion uses 10045 by default"}
+	}
+	return Check{Name: "port", Message: value}
+}
+
+func portFree(value string) Check {
+	ln, err := net.Listen("tcp", ":"+value)
+	if err != nil {
+		return Check{Name: "port free", Status: Warn, Message: err.Error(), Hint: "stop the other listener or change port"}
+	}
+	ln.Close()
+	return Check{Name: "port free", Message: value}
+}
+
+func writableDir(name, dir string) Check {
+	if dir == "" {
+		return Check{Name: name, Status: Fail, Message: "not set"}
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return Check{Name: name, Status: Warn, Message: dir + " does not exist yet", Hint: "it is created on the first import"}
+	}
+	f, err := os.CreateTemp(dir, ".cfcli-doctor-*")
+	if err != nil {
+		return Check{Name: name, Status: Fail, Message: err.Error(), Hint: "point root at a directory you can write to"}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return Check{Name: name, Message: dir}
+}
+
+func libraryDir(name, dir string) Check {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return Check{Name: name, Status: Warn, Message: "not a directory", Hint: "bundle will not find headers there"}
+	}
+	return Check{Name: name, Message: "found"}
+}
+
+func readableFile(name, path string) Check {
+	if path == "" {
+		return Check{Name: name, Message: "not set, new program files start empty"}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return Check{Name: name, Status: Fail, Message: err.Error(), Hint: "create the template or unset templatePath"}
+	}
+	f.Close()
+	return Check{Name: name, Message: path}
+}
+
+func executable(name, command string, vars map[string]string) Check {
+	rendered, err := renderCommand(command, vars)
+	if err != nil || strings.TrimSpace(rendered) == "" {
+		return Check{Name: name, Message: "nothing to check"}
+	}
+	tool := strings.Fields(rendered)[0]
+	if strings.ContainsRune(tool, filepath.Separator) || strings.HasPrefix(tool, ".") {
+		return Check{Name: name, Message: tool + " is produced by the build"}
+	}
+	return lookPath(name, tool)
+}
+
+func lookPath(name, tool string) Check {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return Check{Name: name, Status: Fail, Message: tool + " not found on PATH", Hint: "install it or fix the command"}
+	}
+	return Check{Name: name, Message: path}
+}
@@ -0,0 +1,97 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func validSettings(t *testing.T) Settings {
+	t.Helper()
+	return Settings{
+		Root:           t.TempDir(),
+		Language:       "cpp",
+		ProgramFile:    "main",
+		BuildCommand:   "g++ {{.Path}} -o {{.Dir}}/main",
+		ExecuteCommand: "{{.Dir}}/main",
+		InputPrefix:    "input",
+		OutputPrefix:   "output",
+		Port:           "10045",
+		Layout:         "{{.Contest}}/{{.Code}}",
+		ReimportPolicy: "overwrite",
+		EditorMode:     "foreground",
+		EditorCommand:  "nvim {{.Path}}",
+	}
+}
+
+func failedChecks(checks []Check) map[string]bool {
+	failed := make(map[string]bool)
+	for _, c := range checks {
+		if c.Status == Fail {
+			failed[c.Name] = true
+		}
+	}
+	return failed
+}
+
+func TestValidate_Valid(t *testing.T) {
+	checks := Validate(validSettings(t))
+	if Failed(checks) {
+		t.Errorf("expected valid settings to pass, failed: %v", failedChecks(checks))
+	}
+}
+
+func TestValidate_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Settings)
+		check  string
+	}{
+		{"bad build template", func(s *Settings) { s.BuildCommand = "g++ {{.Path" }, "buildCommand"},
+		{"unknown field", func(s *Settings) { s.ExecuteCommand = "{{.Directory}}/main" }, "executeCommand"},
+		{"missing execute", func(s *Settings) { s.ExecuteCommand = "" }, "executeCommand"},
+		{"missing root", func(s *Settings) { s.Root = "" }, "root"},
+		{"same prefixes", func(s *Settings) { s.OutputPrefix = "in"; s.InputPrefix = "input" }, "test case prefixes"},
+		{"bad port", func(s *Settings) { s.Port = "http" }, "port"},
+		{"bad layout", func(s *Settings) { s.Layout = "{{.Round}}" }, "layout"},
+		{"bad policy", func(s *Settings) { s.ReimportPolicy = "replace" }, "reimportPolicy"},
+		{"bad editor mode", func(s *Settings) { s.EditorMode = "popup" }, "editor"},
+		{"bad checker", func(s *Settings) { s.Checker = "float:x" }, "checker"},
+		{"negative time limit", func(s *Settings) { s.TimeLimit = -1 }, "timeLimit"},
+		{"unknown lint rule", func(s *Settings) { s.LintRules = []string{"tabs"} }, "lintRules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validSettings(t)
+			tt.modify(&s)
+			if failed := failedChecks(Validate(s)); !failed[tt.check] {
+				t.Errorf("expected %q to fail, failed: %v", tt.check, failed)
+			}
+		})
+	}
+}
+
+func TestDiagnose_Environment(t *testing.T) {
+	s := validSettings(t)
+	s.TemplatePath = filepath.Join(s.Root, "missing.cpp")
+	s.ExecuteCommand = "definitely-not-a-real-interpreter {{.Path}}"
+
+	failed := failedChecks(Diagnose(s))
+	if !failed["templatePath"] {
+		t.Errorf("expected missing template to fail")
+	}
+	if !failed["executeCommand tool"] {
+		t.Errorf("expected missing interpreter to fail")
+	}
+	if failed["root writable"] {
+		t.Errorf("expected temp root to be writable")
+	}
+
+	if err := os.WriteFile(s.TemplatePath, []byte("// template"), 0o644); err != nil {
+		t.Fatalf("writing template failed: %v", err)
+	}
+	if failed := failedChecks(Diagnose(s)); failed["templatePath"] {
+		t.Errorf("expected existing template to pass")
+	}
+}
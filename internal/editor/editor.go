@@ -0,0 +1,240 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Mode decides how the editor is started.
+type Mode string
+
+const (
+	// ModeForeground runs editorCommand attached to the current terminal and
+	// waits for it, which is what terminal editors such as nvim need.
+	ModeForeground Mode = "foreground"
+	// ModeBackground starts editorCommand detached, for GUI editors.
+	ModeBackground Mode = "background"
+	// ModeTmuxWindow runs editorCommand in a new tmux window.
+	ModeTmuxWindow Mode = "tmux-window"
+	// ModeTmuxPane runs editorCommand in a new tmux pane next to the current one.
+	ModeTmuxPane Mode = "tmux-pane"
+	// ModeTerminal runs editorCommand inside terminalCommand, e.g. "alacritty -e {{.Command}}".
+	ModeTerminal Mode = "terminal"
+	// ModeNvimServer opens the file in the nvim instance listening on Server,
+	// with RemoteCommand.
+	ModeNvimServer Mode = "nvim-server"
+	// ModeVSCode opens the file in the most recent VS Code window, with
+	// RemoteCommand.
+	ModeVSCode Mode = "vscode"
+)
+
+// defaultRemoteCommands are used by the modes handing the file to a running
+// editor when no RemoteCommand is configured.
+var defaultRemoteCommands = map[Mode]string{
+	ModeNvimServer: "nvim --server {{.Server}} --remote {{.Path}}",
+	ModeVSCode:     "code --reuse-window {{.Path}}",
+}
+
+var modes = []Mode{ModeForeground, ModeBackground, ModeTmuxWindow, ModeTmuxPane, ModeTerminal, ModeNvimServer, ModeVSCode}
+
+func ParseMode(s string) (Mode, error) {
+	for _, mode := range modes {
+		if string(mode) == s {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("invalid editor mode %q (expected one of %v)", s, modes)
+}
+
+type Config struct {
+	Mode            Mode
+	Command         string // editorCommand template
+	TerminalCommand string // terminal wrapper template, used by ModeTerminal
+	Server          string // nvim server address, used by ModeNvimServer
+	RemoteCommand   string // template used by ModeNvimServer and ModeVSCode, "" for their default
+}
+
+// Vars are the values available to the command templates. Tests holds the
+// paths of the problem's test input files; {{join .Tests " "}} lists them.
+type Vars struct {
+	Path  string
+	Dir   string
+	Tests []string
+}
+
+type Launcher struct {
+	mode            Mode
+	command         *template.Template
+	terminalCommand *template.Template
+	remoteCommand   *template.Template
+	server          string
+	logger          *log.Logger
+	foreground      sync.Mutex // held while a foreground editor owns the terminal
+}
+
+var funcs = template.FuncMap{"join": strings.Join}
+
+func NewLauncher(cfg Config, logger *log.Logger) (*Launcher, error) {
+	if _, err := ParseMode(string(cfg.Mode)); err != nil {
+		return nil, err
+	}
+
+	command, err := template.New("editor").Funcs(funcs).Parse(cfg.Command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid editorCommand template: %w", err)
+	}
+	terminalCommand, err := template.New("terminal").Funcs(funcs).Parse(cfg.TerminalCommand)
+	if err != nil {
+		return nil, fmt.Errorf("invalid terminalCommand template: %w", err)
+	}
+
+	remoteSpec := cfg.RemoteCommand
+	if remoteSpec == "" {
+		remoteSpec = defaultRemoteCommands[cfg.Mode]
+	}
+	remoteCommand, err := template.New("remote").Funcs(funcs).Parse(remoteSpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remoteCommand template: %w", err)
+	}
+
+	switch {
+	case cfg.Mode == ModeTerminal && strings.TrimSpace(cfg.TerminalCommand) == "":
+		return nil, fmt.Errorf("editor mode %s needs a terminalCommand", cfg.Mode)
+	case cfg.Mode == ModeNvimServer && cfg.Server == "" && strings.Contains(remoteSpec, ".Server"):
+		return nil, fmt.Errorf("editor mode %s needs an editorServer address", cfg.Mode)
+	}
+
+	return &Launcher{
+		mode:            cfg.Mode,
+		command:         command,
+		terminalCommand: terminalCommand,
+		remoteCommand:   remoteCommand,
+		server:          cfg.Server,
+		logger:          logger,
+	}, nil
+}
+
+// Command returns the arguments of the process that opens the editor. An
+// empty result means no editor is configured.
+func (l *Launcher) Command(vars Vars) ([]string, error) {
+	if l.mode == ModeNvimServer || l.mode == ModeVSCode {
+		remoteCmd, err := render(l.remoteCommand, struct {
+			Vars
+			Server string
+		}{vars, l.server})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render remote command: %w", err)
+		}
+		return strings.Fields(remoteCmd), nil
+	}
+
+	editorVars := vars
+	if l.mode == ModeTmuxWindow || l.mode == ModeTmuxPane {
+		// tmux runs the editor command through the shell.
+		editorVars = quoteVars(vars)
+	}
+	editorCmd, err := render(l.command, editorVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render editor command: %w", err)
+	}
+	if editorCmd == "" {
+		return nil, nil
+	}
+
+	switch l.mode {
+	case ModeTmuxWindow:
+		return []string{"tmux", "new-window", "-c", vars.Dir, editorCmd}, nil
+	case ModeTmuxPane:
+		return []string{"tmux", "split-window", "-h", "-c", vars.Dir, editorCmd}, nil
+	case ModeTerminal:
+		wrapped, err := render(l.terminalCommand, struct {
+			Vars
+			Command string
+		}{vars, editorCmd})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render terminal command: %w", err)
+		}
+		return strings.Fields(wrapped), nil
+	default:
+		return strings.Fields(editorCmd), nil
+	}
+}
+
+// Open starts the editor. Foreground editors are waited for, one at a time
+// as they share the terminal; background and terminal editors are reaped
+// once they exit; the remaining modes only hand the file over to tmux or a
+// running editor, which is waited for too.
+func (l *Launcher) Open(vars Vars) error {
+	args, err := l.Command(vars)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = vars.Dir
+
+	switch l.mode {
+	case ModeBackground, ModeTerminal:
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to launch editor: %w", err)
+		}
+		go func() {
+			if err := cmd.Wait(); err != nil {
+				l.logger.Printf("WARN: editor exited: %v", err)
+			}
+		}()
+		return nil
+
+	case ModeForeground:
+		// Problems imported together open one after the other.
+		l.foreground.Lock()
+		defer l.foreground.Unlock()
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+	default:
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to launch editor: %w", err)
+	}
+	return nil
+}
+
+// quoteVars shell-quotes the paths in vars.
+func quoteVars(vars Vars) Vars {
+	quoted := Vars{Path: shellQuote(vars.Path), Dir: shellQuote(vars.Dir)}
+	for _, test := range vars.Tests {
+		quoted.Tests = append(quoted.Tests, shellQuote(test))
+	}
+	return quoted
+}
+
+// shellQuote quotes s for a POSIX shell, leaving it alone when it holds no
+// characters the shell would interpret.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-.,/:@+=%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func render(t *template.Template, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
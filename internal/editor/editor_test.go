@@ -0,0 +1,126 @@
+package editor
+
+import (
+	"log"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	vars := Vars{
+		Path:  "/p/1985/A/main.cpp",
+		Dir:   "/p/1985/A",
+		Tests: []string{"/p/1985/A/tests/sample-input1", "/p/1985/A/tests/sample-input2"},
+	}
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected []string
+	}{
+		{
+			"foreground",
+			Config{Mode: ModeForeground, Command: "nvim {{.Path}}"},
+			[]string{"nvim", "/p/1985/A/main.cpp"},
+		},
+		{
+			"background with tests",
+			Config{Mode: ModeBackground, Command: "subl {{.Path}} {{join .Tests \" \"}}"},
+			[]string{"subl", "/p/1985/A/main.cpp", "/p/1985/A/tests/sample-input1", "/p/1985/A/tests/sample-input2"},
+		},
+		{
+			"tmux window",
+			Config{Mode: ModeTmuxWindow, Command: "nvim {{.Path}}"},
+			[]string{"tmux", "new-window", "-c", "/p/1985/A", "nvim /p/1985/A/main.cpp"},
+		},
+		{
+			"tmux pane",
+			Config{Mode: ModeTmuxPane, Command: "nvim {{.Path}}"},
+			[]string{"tmux", "split-window", "-h", "-c", "/p/1985/A", "nvim /p/1985/A/main.cpp"},
+		},
+		{
+			"terminal",
+			Config{Mode: ModeTerminal, Command: "nvim {{.Path}}", TerminalCommand: "alacritty --working-directory {{.Dir}} -e {{.Command}}"},
+			[]string{"alacritty", "--working-directory", "/p/1985/A", "-e", "nvim", "/p/1985/A/main.cpp"},
+		},
+		{
+			"nvim server",
+			Config{Mode: ModeNvimServer, Server: "/tmp/nvim.sock"},
+			[]string{"nvim", "--server", "/tmp/nvim.sock", "--remote", "/p/1985/A/main.cpp"},
+		},
+		{
+			"vscode",
+			Config{Mode: ModeVSCode},
+			[]string{"code", "--reuse-window", "/p/1985/A/main.cpp"},
+		},
+		{
+			"nvim server with tests",
+			Config{Mode: ModeNvimServer, Server: "/tmp/nvim.sock", RemoteCommand: "nvim --server {{.Server}} --remote-tab {{.Path}} {{join .Tests \" \"}}"},
+			[]string{"nvim", "--server", "/tmp/nvim.sock", "--remote-tab", "/p/1985/A/main.cpp", "/p/1985/A/tests/sample-input1", "/p/1985/A/tests/sample-input2"},
+		},
+		{
+			"vscode folder",
+			Config{Mode: ModeVSCode, RemoteCommand: "code --reuse-window {{.Dir}} {{.Path}}"},
+			[]string{"code", "--reuse-window", "/p/1985/A", "/p/1985/A/main.cpp"},
+		},
+		{
+			"no editor",
+			Config{Mode: ModeForeground, Command: ""},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewLauncher(tt.cfg, log.New(os.Stdout, "[test] ", 0))
+			if err != nil {
+				t.Fatalf("NewLauncher failed: %v", err)
+			}
+			args, err := l.Command(vars)
+			if err != nil {
+				t.Fatalf("Command failed: %v", err)
+			}
+			if !reflect.DeepEqual(args, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, args)
+			}
+		})
+	}
+}
+
+func TestCommand_TmuxQuoting(t *testing.T) {
+	vars := Vars{
+		Path:  "/home/me/my problems/1985/A/main.cpp",
+		Dir:   "/home/me/my problems/1985/A",
+		Tests: []string{"/home/me/my problems/1985/A/tests/it's-input1"},
+	}
+	l, err := NewLauncher(Config{Mode: ModeTmuxPane, Command: "nvim {{.Path}} {{join .Tests \" \"}}"}, log.New(os.Stdout, "[test] ", 0))
+	if err != nil {
+		t.Fatalf("NewLauncher failed: %v", err)
+	}
+	args, err := l.Command(vars)
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	expected := []string{"tmux", "split-window", "-h", "-c", vars.Dir,
+		`nvim '/home/me/my problems/1985/A/main.cpp' '/home/me/my problems/1985/A/tests/it'\''s-input1'`}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %q, got %q", expected, args)
+	}
+}
+
+func TestNewLauncher_Invalid(t *testing.T) {
+	logger := log.New(os.Stdout, "[test] ", 0)
+	configs := []Config{
+		{Mode: "emacs-daemon", Command: "emacs {{.Path}}"},
+		{Mode: ModeForeground, Command: "nvim {{.Path"},
+		{Mode: ModeTerminal, Command: "nvim {{.Path}}"},
+		{Mode: ModeNvimServer},
+		{Mode: ModeVSCode, RemoteCommand: "code {{.Path"},
+	}
+	for _, cfg := range configs {
+		if _, err := NewLauncher(cfg, logger); err == nil {
+			t.Errorf("expected error for config %+v", cfg)
+		}
+	}
+}
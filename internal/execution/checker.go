@@ -0,0 +1,66 @@
+package execution
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Checker decides whether a program's output is accepted for the expected output.
+type Checker func(expected, actual string) bool
+
+// ParseChecker builds a checker from its configuration value:
+//
+//	exact      outputs must match after trimming surrounding whitespace (default)
+//	tokens     outputs must match token by token, ignoring whitespace
+//	float:EPS  like tokens, but numbers may differ by EPS (absolute or relative)
+func ParseChecker(spec string) (Checker, error) {
+	name, arg, _ := strings.Cut(spec, ":")
+	switch name {
+	case "", "exact":
+		return exactChecker, nil
+	case "tokens":
+		return tokenChecker(nil), nil
+	case "float":
+		eps, err := strconv.ParseFloat(arg, 64)
+		if err != nil || eps < 0 {
+			return nil, fmt.Errorf("invalid checker %q: expected float:<epsilon>, e.g. float:1e-6", spec)
+		}
+		return tokenChecker(func(expected, actual string) bool {
+			e, errE := strconv.ParseFloat(expected, 64)
+			a, errA := strconv.ParseFloat(actual, 64)
+			if errE != nil || errA != nil {
+				return expected == actual
+			}
+			diff := math.Abs(e - a)
+			return diff <= eps || diff <= eps*math.Abs(e)
+		}), nil
+	default:
+		return nil, fmt.Errorf("invalid checker %q (expected exact, tokens or float:<epsilon>)", spec)
+	}
+}
+
+func exactChecker(expected, actual string) bool {
+	return strings.TrimSpace(expected) == strings.TrimSpace(actual)
+}
+
+// tokenChecker compares outputs token by token, using equal for each pair of
+// tokens or plain string equality when it is nil.
+func tokenChecker(equal func(expected, actual string) bool) Checker {
+	if equal == nil {
+		equal = func(expected, actual string) bool { return expected == actual }
+	}
+	return func(expected, actual string) bool {
+		exp, act := strings.Fields(expected), strings.Fields(actual)
+		if len(exp) != len(act) {
+			return false
+		}
+		for i := range exp {
+			if !equal(exp[i], act[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,39 @@
+package execution
+
+import "testing"
+
+func TestParseChecker(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected string
+		actual   string
+		ok       bool
+	}{
+		{"exact", "1 2\n", "1 2", true},
+		{"exact", "1 2", "1  2", false},
+		{"tokens", "1 2\n3", "1\n2 3\n", true},
+		{"tokens", "1 2", "1 2 3", false},
+		{"float:1e-6", "0.3333333", "0.33333334", true},
+		{"float:1e-6", "0.333", "0.334", false},
+		{"float:1e-6", "1000000000", "1000000100", true}, // within relative error
+		{"float:1e-6", "YES 0.5", "NO 0.5", false},
+	}
+
+	for _, tt := range tests {
+		checker, err := ParseChecker(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseChecker(%q) failed: %v", tt.spec, err)
+		}
+		if got := checker(tt.expected, tt.actual); got != tt.ok {
+			t.Errorf("%s(%q, %q): expected %v, got %v", tt.spec, tt.expected, tt.actual, tt.ok, got)
+		}
+	}
+}
+
+func TestParseChecker_Invalid(t *testing.T) {
+	for _, spec := range []string{"float", "float:abc", "float:-1", "regex"} {
+		if _, err := ParseChecker(spec); err == nil {
+			t.Errorf("expected error for checker %q", spec)
+		}
+	}
+}
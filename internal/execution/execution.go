@@ -2,16 +2,25 @@ package execution
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// TestsDir is the directory, inside a problem directory, holding grouped test
+// files such as sample-input1 or custom-output2. Test files directly in the
+// problem directory (input1, output1, ...) are still read as ungrouped tests.
+const TestsDir = "tests"
+
 type Engine struct {
 	root             string
 	testCasesDir     string
@@ -19,6 +28,10 @@ type Engine struct {
 	executionCommand string // ./main.exe
 	inputPrefix      string
 	outputPrefix     string
+	checker          Checker
+	timeLimit        time.Duration
+	multitest        *Multitest
+	stderr           io.Writer
 	logger           *log.Logger
 }
 
@@ -38,15 +51,79 @@ func NewEngine(
 		executionCommand: executionCommand,
 		inputPrefix:      inputPrefix,
 		outputPrefix:     outputPrefix,
+		checker:          exactChecker,
+		stderr:           os.Stderr,
 		logger:           logger,
 	}
 }
 
+// SetChecker replaces the default exact comparison of outputs.
+func (e *Engine) SetChecker(c Checker) {
+	e.checker = c
+}
+
+// SetTimeLimit kills test runs exceeding d and reports them as TLE. Zero
+// disables the limit.
+func (e *Engine) SetTimeLimit(d time.Duration) {
+	e.timeLimit = d
+}
+
+// SetMultitest makes failed tests that pack several cases be split into
+// them, running each case on its own to find the ones failing. nil disables
+// this.
+func (e *Engine) SetMultitest(m *Multitest) {
+	e.multitest = m
+}
+
+// SetStderr sends what the program writes to stderr to w instead of
+// os.Stderr.
+func (e *Engine) SetStderr(w io.Writer) {
+	e.stderr = w
+}
+
+type Verdict string
+
+const (
+	VerdictOK           Verdict = "OK"
+	VerdictWrongAnswer  Verdict = "WA"
+	VerdictRuntimeError Verdict = "RE"
+	VerdictTimeLimit    Verdict = "TLE"
+	VerdictCompileError Verdict = "CE" // the build failed, so no test ran
+)
+
 type Result struct {
 	Ok             bool
 	TestCase       int
+	Group          string
+	Verdict        Verdict
+	Time           time.Duration
+	Memory         int64 // peak resident set size in bytes, 0 if unknown
+	Input          string
 	ExpectedOutput string
 	ProgramOutput  string
+	Split          *Split // set for a failed test split into its cases
+}
+
+// Name identifies the test case the way its files are named, e.g. "sample-2".
+func (r Result) Name() string {
+	if r.Group == "" {
+		return strconv.Itoa(r.TestCase)
+	}
+	return fmt.Sprintf("%s-%d", r.Group, r.TestCase)
+}
+
+// BuildError reports that the build command failed, as opposed to the
+// engine being unable to run the tests.
+type BuildError struct {
+	Err error
+}
+
+func (e *BuildError) Error() string {
+	return fmt.Sprintf("build failed: %v", e.Err)
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
 }
 
 type TestCase struct {
@@ -56,7 +133,7 @@ type TestCase struct {
 
 func (e *Engine) Execute() ([]Result, error) {
 	if e.buildCommand != "" {
-		err := e.build()
+		err := e.Build()
 		if err != nil {
 			return nil, err
 		}
@@ -67,19 +144,27 @@ func (e *Engine) Execute() ([]Result, error) {
 		return nil, err
 	}
 
+	keys := make([]testKey, 0, len(testCases))
+	for k := range testCases {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
 	results := make([]Result, 0, len(testCases))
 
-	for k, v := range testCases {
-		programOutput, ok, err := e.runTestCase(k, v)
+	for _, k := range keys {
+		result, err := e.runTestCase(k.num, testCases[k])
 		if err != nil {
 			return nil, err
 		}
-
-		result := Result{
-			Ok:             ok,
-			TestCase:       k,
-			ExpectedOutput: v.Output,
-			ProgramOutput:  programOutput,
+		result.Group = k.group
+
+		if !result.Ok && e.multitest != nil {
+			split, err := e.splitTestCase(k.num, testCases[k])
+			if err != nil {
+				e.logger.Printf("WARN: test %s was not split into cases: %v", result.Name(), err)
+			}
+			result.Split = split
 		}
 
 		results = append(results, result)
@@ -88,55 +173,117 @@ func (e *Engine) Execute() ([]Result, error) {
 	return results, nil
 }
 
-func (e *Engine) build() error {
+// Build runs the build command in the root directory, sending its output to
+// stderr. A failing command is reported as a *BuildError. Without a build
+// command there is nothing to do.
+func (e *Engine) Build() error {
+	if e.buildCommand == "" {
+		return nil
+	}
 	e.logger.Println("Building program...")
 	args := strings.Split(e.buildCommand, " ")
 
 	cmd := exec.Command(args[0], args[1:]...)
 	cmd.Dir = e.root
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		fmt.Printf("Build failed: %v\n", err)
-		return err
+		e.logger.Printf("ERROR: Build failed: %v\n", err)
+		return &BuildError{Err: err}
 	}
 
 	return nil
 }
 
-func (e *Engine) readTestCases() (map[int]TestCase, error) {
-	testCases := make(map[int]TestCase)
+// testKey identifies a test case by its group ("" for ungrouped files) and number.
+type testKey struct {
+	group string
+	num   int
+}
 
-	entries, err := os.ReadDir(e.testCasesDir)
+func (k testKey) less(o testKey) bool {
+	if rk, ro := groupRank(k.group), groupRank(o.group); rk != ro {
+		return rk < ro
+	}
+	if k.group != o.group {
+		return k.group < o.group
+	}
+	return k.num < o.num
+}
+
+// groupRank orders ungrouped tests first, then samples, then everything else.
+func groupRank(group string) int {
+	switch group {
+	case "":
+		return 0
+	case "sample":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (e *Engine) readTestCases() (map[testKey]TestCase, error) {
+	testCases := make(map[testKey]TestCase)
+
+	if err := e.readTestCaseDir(e.testCasesDir, false, testCases); err != nil {
+		return nil, err
+	}
+
+	groupedDir := filepath.Join(e.testCasesDir, TestsDir)
+	if _, err := os.Stat(groupedDir); err == nil {
+		if err := e.readTestCaseDir(groupedDir, true, testCases); err != nil {
+			return nil, err
+		}
+	}
+
+	return testCases, nil
+}
+
+func (e *Engine) readTestCaseDir(dir string, grouped bool, testCases map[testKey]TestCase) error {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		e.logger.Printf("failed to read the testcases dir: %s\n", err)
-		return nil, err
+		return err
 	}
 
 	for _, entry := range entries {
 		if entry.IsDir() {
+			if !grouped && entry.Name() == TestsDir {
+				continue
+			}
 			e.logger.Printf("WARN: only files allowed in the test directory: %s", entry.Name())
 			continue
 		}
 
 		fileName := entry.Name()
+		baseName := fileName
+
+		var group string
+		if grouped {
+			var found bool
+			group, baseName, found = strings.Cut(fileName, "-")
+			if !found {
+				continue
+			}
+		}
 
 		var testCaseNumStr string
 		var isInput bool
 
-		if strings.HasPrefix(fileName, e.inputPrefix) {
+		if strings.HasPrefix(baseName, e.inputPrefix) {
 			isInput = true
-		} else if strings.HasPrefix(fileName, e.outputPrefix) {
+		} else if strings.HasPrefix(baseName, e.outputPrefix) {
 			isInput = false
 		} else {
 			continue
 		}
 
 		if isInput {
-			testCaseNumStr = fileName[len(e.inputPrefix):]
+			testCaseNumStr = baseName[len(e.inputPrefix):]
 		} else {
-			testCaseNumStr = fileName[len(e.outputPrefix):]
+			testCaseNumStr = baseName[len(e.outputPrefix):]
 		}
 
 		testCaseNum, err := strconv.Atoi(testCaseNumStr)
@@ -145,14 +292,15 @@ func (e *Engine) readTestCases() (map[int]TestCase, error) {
 			continue
 		}
 
-		filePath := filepath.Join(e.testCasesDir, fileName)
+		filePath := filepath.Join(dir, fileName)
 		content, err := os.ReadFile(filePath)
 		if err != nil {
 			e.logger.Printf("WARN: failed to read file contents for: %s", fileName)
 			continue
 		}
 
-		testCase := testCases[testCaseNum]
+		key := testKey{group: group, num: testCaseNum}
+		testCase := testCases[key]
 
 		if isInput {
 			testCase.Input = string(content)
@@ -160,17 +308,40 @@ func (e *Engine) readTestCases() (map[int]TestCase, error) {
 			testCase.Output = string(content)
 		}
 
-		testCases[testCaseNum] = testCase
+		testCases[key] = testCase
 	}
 
-	return testCases, nil
+	return nil
+}
+
+// Run runs the built program on a single test, outside of the tests of the
+// problem.
+func (e *Engine) Run(t TestCase) (Result, error) {
+	return e.runTestCase(0, t)
 }
 
-func (e *Engine) runTestCase(testNum int, t TestCase) (string, bool, error) {
+// runTestCase runs the program on one test. Runtime errors and timeouts are
+// verdicts; an error is only returned when the program cannot be run at all.
+func (e *Engine) runTestCase(testNum int, t TestCase) (Result, error) {
+	result := Result{
+		TestCase:       testNum,
+		Input:          t.Input,
+		ExpectedOutput: t.Output,
+	}
+
+	ctx := context.Background()
+	if e.timeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeLimit)
+		defer cancel()
+	}
+
 	args := strings.Split(e.executionCommand, " ")
 
-	cmd := exec.Command(args[0], args[1:]...)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 	cmd.Dir = e.root
+	// Don't wait on children still holding stdout once the program is killed.
+	cmd.WaitDelay = 100 * time.Millisecond
 
 	inputReader := strings.NewReader(t.Input)
 	cmd.Stdin = inputReader
@@ -178,24 +349,81 @@ func (e *Engine) runTestCase(testNum int, t TestCase) (string, bool, error) {
 	var out bytes.Buffer
 	cmd.Stdout = &out
 
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = e.stderr
 
-	if err := cmd.Run(); err != nil {
+	started := time.Now()
+	runErr := cmd.Run()
+	result.Time = time.Since(started)
+	result.ProgramOutput = out.String()
+	if cmd.ProcessState != nil {
+		result.Memory = peakMemory(cmd.ProcessState)
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		e.logger.Printf("WARN: test %d exceeded the time limit of %s", testNum, e.timeLimit)
+		result.Verdict = VerdictTimeLimit
+	case errors.As(runErr, &exitErr):
+		e.logger.Printf("WARN: test %d exited with %s", testNum, exitErr)
+		result.Verdict = VerdictRuntimeError
+	case runErr != nil:
 		e.logger.Printf("ERROR: Failed to execute test %d\n", testNum)
-		return "", false, err
+		return result, runErr
+	case !e.checker(t.Output, result.ProgramOutput):
+		result.Verdict = VerdictWrongAnswer
+	default:
+		result.Verdict = VerdictOK
+		result.Ok = true
 	}
 
-	outBytes, err := io.ReadAll(&out)
+	return result, nil
+}
+
+// splitTestCase runs each case of a multi-case test as a test of its own.
+// It returns an error when the test can't be split.
+func (e *Engine) splitTestCase(testNum int, t TestCase) (*Split, error) {
+	inputs, err := SplitInput(t.Input, e.multitest.InputLines)
 	if err != nil {
-		e.logger.Printf("ERROR: Failed to read output for the test: %d. %s\n", testNum, err)
-		return "", false, err
+		return nil, err
+	}
+	outputs, err := SplitOutput(t.Output, len(inputs), e.multitest.OutputLines)
+	if err != nil {
+		return nil, err
 	}
 
-	outStr := string(outBytes)
-
-	if strings.TrimSpace(outStr) != strings.TrimSpace(t.Output) {
-		return outStr, false, nil
+	split := &Split{Total: len(inputs)}
+	for i := range inputs {
+		r, err := e.runTestCase(testNum, TestCase{Input: "1\n" + inputs[i], Output: outputs[i]})
+		if err != nil {
+			return nil, err
+		}
+		if !r.Ok {
+			split.Failed = append(split.Failed, Subcase{
+				Index:          i + 1,
+				Verdict:        r.Verdict,
+				Input:          r.Input,
+				ExpectedOutput: r.ExpectedOutput,
+				ProgramOutput:  r.ProgramOutput,
+			})
+		}
 	}
+	return split, nil
+}
 
-	return outStr, true, nil
+// DiffSummary describes the first line where actual differs from expected,
+// ignoring surrounding whitespace, or returns "" when they match.
+func DiffSummary(expected, actual string) string {
+	exp := strings.Split(strings.TrimSpace(expected), "\n")
+	act := strings.Split(strings.TrimSpace(actual), "\n")
+
+	for i := 0; i < len(exp) && i < len(act); i++ {
+		if strings.TrimSpace(exp[i]) != strings.TrimSpace(act[i]) {
+			return fmt.Sprintf("line %d: expected %q, got %q", i+1, strings.TrimSpace(exp[i]), strings.TrimSpace(act[i]))
+		}
+	}
+	if len(exp) != len(act) {
+		return fmt.Sprintf("expected %d line(s), got %d", len(exp), len(act))
+	}
+	return ""
 }
@@ -1,10 +1,12 @@
 package execution
 
 import (
+	"errors"
 	"log"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestExecutionEngine_RunCPP(t *testing.T) {
@@ -58,3 +60,128 @@ int main() {
 		t.Errorf("expected test to pass, but it failed. Output: %s", results[0].ProgramOutput)
 	}
 }
+
+func TestExecutionEngine_GroupedTests(t *testing.T) {
+	tmpDir := t.TempDir()
+	testsDir := filepath.Join(tmpDir, TestsDir)
+	if err := os.Mkdir(testsDir, 0o755); err != nil {
+		t.Fatalf("failed to create tests dir: %v", err)
+	}
+
+	files := map[string]string{
+		filepath.Join(tmpDir, "input1"):             "legacy",
+		filepath.Join(tmpDir, "output1"):            "legacy",
+		filepath.Join(testsDir, "custom-input1"):    "custom",
+		filepath.Join(testsDir, "custom-output1"):   "wrong",
+		filepath.Join(testsDir, "sample-input2"):    "two",
+		filepath.Join(testsDir, "sample-output2"):   "two",
+		filepath.Join(testsDir, "sample-input1"):    "one",
+		filepath.Join(testsDir, "sample-output1"):   "one",
+		filepath.Join(testsDir, "unrelated-notes"):  "ignored",
+		filepath.Join(testsDir, "sample-outputXYZ"): "ignored",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	engine := NewEngine(tmpDir, tmpDir, "", "cat", "input", "output", log.New(os.Stdout, "TEST: ", log.LstdFlags))
+
+	results, err := engine.Execute()
+	if err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+
+	expected := []struct {
+		name string
+		ok   bool
+	}{
+		{"1", true},
+		{"sample-1", true},
+		{"sample-2", true},
+		{"custom-1", false},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i, want := range expected {
+		if results[i].Name() != want.name || results[i].Ok != want.ok {
+			t.Errorf("result %d: expected %s ok=%v, got %s ok=%v", i, want.name, want.ok, results[i].Name(), results[i].Ok)
+		}
+	}
+}
+
+func TestExecutionEngine_Verdicts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	script := `read x
+case "$x" in
+  crash) exit 3 ;;
+  slow) exec sleep 5 ;;
+  *) echo "$x" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "run.sh"), []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	tests := map[string]string{
+		"input1": "ok\n", "output1": "ok\n",
+		"input2": "crash\n", "output2": "crash\n",
+		"input3": "slow\n", "output3": "slow\n",
+		"input4": "wrong\n", "output4": "right\n",
+	}
+	for name, content := range tests {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	engine := NewEngine(tmpDir, tmpDir, "", "sh run.sh", "input", "output", log.New(os.Stdout, "TEST: ", log.LstdFlags))
+	engine.SetTimeLimit(500 * time.Millisecond)
+
+	results, err := engine.Execute()
+	if err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+
+	expected := []Verdict{VerdictOK, VerdictRuntimeError, VerdictTimeLimit, VerdictWrongAnswer}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i, want := range expected {
+		if results[i].Verdict != want {
+			t.Errorf("test %d: expected %s, got %s", i+1, want, results[i].Verdict)
+		}
+	}
+	if results[2].Time > 3*time.Second {
+		t.Errorf("expected the slow test to be killed, took %s", results[2].Time)
+	}
+}
+
+func TestDiffSummary(t *testing.T) {
+	tests := []struct {
+		expected, actual, want string
+	}{
+		{"1\n2\n", "1\n2", ""},
+		{"1\n2\n", "1\n3\n", `line 2: expected "2", got "3"`},
+		{"1\n2\n", "1\n", "expected 2 line(s), got 1"},
+	}
+	for _, tt := range tests {
+		if got := DiffSummary(tt.expected, tt.actual); got != tt.want {
+			t.Errorf("DiffSummary(%q, %q) = %q, want %q", tt.expected, tt.actual, got, tt.want)
+		}
+	}
+}
+
+func TestExecutionEngine_BuildError(t *testing.T) {
+	tmpDir := t.TempDir()
+	engine := NewEngine(tmpDir, tmpDir, "false", "cat", "input", "output", log.New(os.Stdout, "TEST: ", log.LstdFlags))
+
+	_, err := engine.Execute()
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("expected a BuildError, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build darwin
+
+package execution
+
+import (
+	"os"
+	"syscall"
+)
+
+// peakMemory returns the maximum resident set size of a finished process.
+// On macOS ru_maxrss is reported in bytes.
+func peakMemory(state *os.ProcessState) int64 {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		return int64(usage.Maxrss)
+	}
+	return 0
+}
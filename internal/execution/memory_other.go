@@ -0,0 +1,10 @@
+//go:build !unix
+
+package execution
+
+import "os"
+
+// peakMemory is not available on this platform.
+func peakMemory(state *os.ProcessState) int64 {
+	return 0
+}
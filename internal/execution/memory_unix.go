@@ -0,0 +1,17 @@
+//go:build unix && !darwin
+
+package execution
+
+import (
+	"os"
+	"syscall"
+)
+
+// peakMemory returns the maximum resident set size of a finished process.
+// On Linux and the BSDs ru_maxrss is reported in kilobytes.
+func peakMemory(state *os.ProcessState) int64 {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		return int64(usage.Maxrss) * 1024
+	}
+	return 0
+}
@@ -0,0 +1,207 @@
+package execution
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Multitest describes how a test packing several cases, Codeforces style,
+// is split into them: the input starts with a line holding the number of
+// cases t, followed by the cases, and the expected output holds the answers
+// in the same order.
+type Multitest struct {
+	InputLines  int // input lines per case, 0 to guess
+	OutputLines int // expected output lines per case, 0 to guess
+}
+
+// Split is what running the cases of a failed test one by one found.
+type Split struct {
+	Total  int
+	Failed []Subcase
+}
+
+// Subcase is a case of a multi-case test that failed on its own. Its input
+// is a complete test of one case.
+type Subcase struct {
+	Index          int // 1-based
+	Verdict        Verdict
+	Input          string
+	ExpectedOutput string
+	ProgramOutput  string
+}
+
+// SplitInput splits a multi-case input into the lines of each case, without
+// the count line. With linesPerCase 0 the cases are guessed: equal blocks
+// of lines shaped alike, or else blocks whose first line announces their
+// size the same usual way ("n" followed by n values, n lines or n-1 edges;
+// "n m" followed by m lines). Blank lines are ignored.
+func SplitInput(input string, linesPerCase int) ([]string, error) {
+	lines := nonBlankLines(input)
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+	t, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil || t <= 0 {
+		return nil, fmt.Errorf("first line %q is not a number of test cases", lines[0])
+	}
+	lines = lines[1:]
+
+	var sizes []int
+	switch {
+	case linesPerCase > 0:
+		if len(lines) != t*linesPerCase {
+			return nil, fmt.Errorf("%d case(s) of %d line(s) don't fit the %d line(s) of input", t, linesPerCase, len(lines))
+		}
+		sizes = equalSizes(t, linesPerCase)
+	case len(lines)%t == 0 && sameShape(lines, len(lines)/t):
+		sizes = equalSizes(t, len(lines)/t)
+	default:
+		sizes = guessSizes(lines, t)
+		if sizes == nil {
+			return nil, fmt.Errorf("can't tell where the %d case(s) of the input end; set the lines per case", t)
+		}
+	}
+
+	cases := make([]string, 0, t)
+	for _, size := range sizes {
+		cases = append(cases, strings.Join(lines[:size], "\n")+"\n")
+		lines = lines[size:]
+	}
+	return cases, nil
+}
+
+// SplitOutput splits the expected output of t cases. With linesPerCase 0
+// it must hold a multiple of t lines, or exactly t tokens.
+func SplitOutput(output string, t, linesPerCase int) ([]string, error) {
+	lines := nonBlankLines(output)
+	if linesPerCase == 0 && len(lines)%t == 0 {
+		linesPerCase = len(lines) / t
+	}
+	if linesPerCase > 0 && len(lines) == t*linesPerCase {
+		cases := make([]string, 0, t)
+		for i := 0; i < t; i++ {
+			cases = append(cases, strings.Join(lines[i*linesPerCase:(i+1)*linesPerCase], "\n")+"\n")
+		}
+		return cases, nil
+	}
+	if tokens := strings.Fields(output); len(tokens) == t {
+		cases := make([]string, 0, t)
+		for _, token := range tokens {
+			cases = append(cases, token+"\n")
+		}
+		return cases, nil
+	}
+	return nil, fmt.Errorf("can't split %d line(s) of expected output into %d case(s); set the output lines per case", len(lines), t)
+}
+
+func nonBlankLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func equalSizes(t, size int) []int {
+	sizes := make([]int, t)
+	for i := range sizes {
+		sizes[i] = size
+	}
+	return sizes
+}
+
+// sameShape reports whether the blocks of size lines all have the same
+// number of tokens on each line, as cases of a fixed format do.
+func sameShape(lines []string, size int) bool {
+	for i := size; i < len(lines); i++ {
+		if len(strings.Fields(lines[i])) != len(strings.Fields(lines[i%size])) {
+			return false
+		}
+	}
+	return true
+}
+
+// caseRule gives the size, in lines, of a case from its first line and the
+// line after it (nil at the end), or 0 when the case doesn't fit the rule.
+type caseRule func(header []int, next []string) int
+
+// caseRules are the usual ways a case announces its size, the most likely
+// first.
+var caseRules = []caseRule{
+	// n, then n values or a string of length n
+	func(header []int, next []string) int {
+		if len(next) == header[0] || len(next) == 1 && len(next[0]) == header[0] {
+			return 2
+		}
+		return 0
+	},
+	// n, then n lines
+	func(header []int, next []string) int {
+		return 1 + header[0]
+	},
+	// n, then the n-1 edges of a tree
+	func(header []int, next []string) int {
+		if len(header) != 1 {
+			return 0
+		}
+		return header[0]
+	},
+	// n m, then m lines
+	func(header []int, next []string) int {
+		if len(header) < 2 {
+			return 0
+		}
+		return 1 + header[1]
+	},
+}
+
+// guessSizes finds the sizes of t cases covering lines by applying the same
+// rule to every case, or returns nil. Every case starts with a line shaped
+// like the first one.
+func guessSizes(lines []string, t int) []int {
+	for _, rule := range caseRules {
+		if sizes := applyRule(rule, lines, t); sizes != nil {
+			return sizes
+		}
+	}
+	return nil
+}
+
+func applyRule(rule caseRule, lines []string, t int) []int {
+	headerLen := len(strings.Fields(lines[0]))
+	var sizes []int
+	for pos := 0; pos < len(lines); {
+		fields := strings.Fields(lines[pos])
+		if len(fields) != headerLen || len(sizes) == t {
+			return nil
+		}
+		header := make([]int, 0, len(fields))
+		for _, field := range fields {
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				break
+			}
+			header = append(header, n)
+		}
+		if len(header) == 0 {
+			return nil
+		}
+		var next []string
+		if pos+1 < len(lines) {
+			next = strings.Fields(lines[pos+1])
+		}
+		size := rule(header, next)
+		if size < 1 || pos+size > len(lines) {
+			return nil
+		}
+		sizes = append(sizes, size)
+		pos += size
+	}
+	if len(sizes) != t {
+		return nil
+	}
+	return sizes
+}
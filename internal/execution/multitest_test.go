@@ -0,0 +1,107 @@
+package execution
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		linesPerCase int
+		want         []string
+	}{
+		{"arrays", "3\n2\n1 2\n3\n1 2 3\n1\n5\n", 0, []string{"2\n1 2\n", "3\n1 2 3\n", "1\n5\n"}},
+		{"equal shape", "2\n1 2\n3 4\n", 0, []string{"1 2\n", "3 4\n"}},
+		{"n lines", "2\n2\nab\ncd\n1\nxyz\n", 0, []string{"2\nab\ncd\n", "1\nxyz\n"}},
+		{"tree edges", "2\n3\n1 2\n2 3\n2\n1 2\n", 0, []string{"3\n1 2\n2 3\n", "2\n1 2\n"}},
+		{"n m", "2\n3 2\n1 2\n2 3\n2 1\n1 2\n", 0, []string{"3 2\n1 2\n2 3\n", "2 1\n1 2\n"}},
+		{"blank lines", "2\n\n1\n\n2\n", 0, []string{"1\n", "2\n"}},
+		{"configured", "2\n5 6\n7\n1 2\n3\n", 2, []string{"5 6\n7\n", "1 2\n3\n"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitInput(tt.input, tt.linesPerCase)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: SplitInput = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "x\n1\n", "2\n1 2\n"} {
+		if _, err := SplitInput(input, 0); err == nil {
+			t.Errorf("expected SplitInput(%q) to fail", input)
+		}
+	}
+	if _, err := SplitInput("2\n1\n2\n3\n", 1); err == nil {
+		t.Errorf("expected a line count that doesn't fit to fail")
+	}
+}
+
+func TestSplitOutput(t *testing.T) {
+	tests := []struct {
+		output       string
+		t            int
+		linesPerCase int
+		want         []string
+	}{
+		{"YES\nNO\n", 2, 0, []string{"YES\n", "NO\n"}},
+		{"1\n2\n3\n4\n", 2, 0, []string{"1\n2\n", "3\n4\n"}},
+		{"3 5\n", 2, 0, []string{"3\n", "5\n"}},
+		{"1\n2\n3\n4\n", 2, 2, []string{"1\n2\n", "3\n4\n"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitOutput(tt.output, tt.t, tt.linesPerCase)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitOutput(%q, %d, %d) = %q, %v, want %q", tt.output, tt.t, tt.linesPerCase, got, err, tt.want)
+		}
+	}
+	if _, err := SplitOutput("YES\n1 2\nNO\n", 2, 0); err == nil {
+		t.Errorf("expected uneven output to fail")
+	}
+}
+
+func TestExecutionEngine_Multitest(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Doubles each number, except 3.
+	script := `read t
+while [ "$t" -gt 0 ]; do
+  read n
+  if [ "$n" -eq 3 ]; then echo 7; else echo $((n * 2)); fi
+  t=$((t - 1))
+done
+`
+	files := map[string]string{
+		"run.sh":  script,
+		"input1":  "3\n1\n3\n5\n",
+		"output1": "2\n6\n10\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	engine := NewEngine(tmpDir, tmpDir, "", "sh run.sh", "input", "output", log.New(os.Stdout, "TEST: ", log.LstdFlags))
+	engine.SetMultitest(&Multitest{})
+
+	results, err := engine.Execute()
+	if err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Ok {
+		t.Fatalf("expected the test to fail, got %+v", results)
+	}
+
+	split := results[0].Split
+	if split == nil || split.Total != 3 || len(split.Failed) != 1 {
+		t.Fatalf("unexpected split: %+v", split)
+	}
+	want := Subcase{Index: 2, Verdict: VerdictWrongAnswer, Input: "1\n3\n", ExpectedOutput: "6\n", ProgramOutput: "7\n"}
+	if split.Failed[0] != want {
+		t.Errorf("failed case = %+v, want %+v", split.Failed[0], want)
+	}
+}
@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/ccparser"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+// Stages of the pipeline that precede the directorymanager stages.
+const (
+	StageDecode   = "decode"
+	StageParse    = "parse"
+	StageTemplate = "template"
+)
+
+type Options struct {
+	InputPrefix  string
+	OutputPrefix string
+	Policy       directorymanager.ReimportPolicy
+	ProgramFile  string
+	TemplatePath string
+	// Lookup finds a Codeforces problem in the problemset to add its tags
+	// and rating to problem.json. nil skips this.
+	Lookup func(contestID int, index string) *cfapi.Problem
+}
+
+// Importer turns a Competitive Companion payload into a problem directory.
+// The listen server and the import command share it, so a recorded payload
+// goes through exactly the same steps as a live one.
+type Importer struct {
+	dm     *directorymanager.DirectoryManager
+	opts   Options
+	logger *log.Logger
+}
+
+type Result struct {
+	Problem     directorymanager.Problem
+	Dir         string
+	RelativeDir string
+	ProgramFile string
+}
+
+// Error reports the pipeline stage at which an import failed.
+type Error struct {
+	Stage string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewImporter(dm *directorymanager.DirectoryManager, opts Options, logger *log.Logger) *Importer {
+	return &Importer{
+		dm:     dm,
+		opts:   opts,
+		logger: logger,
+	}
+}
+
+// Import decodes a CCProblem payload and writes its problem directory. The
+// payload may also be a problem.json written by an earlier import, in which
+// case the fields recorded by codeforces-cli are kept.
+func (i *Importer) Import(payload []byte) (*Result, error) {
+	var meta directorymanager.Metadata
+	if err := json.Unmarshal(payload, &meta); err != nil {
+		return nil, &Error{Stage: StageDecode, Err: fmt.Errorf("invalid JSON: %w", err)}
+	}
+	if meta.ImportedAt.IsZero() {
+		meta.ImportedAt = time.Now()
+	}
+
+	parsedProblem, err := ccparser.NewParser(i.logger).Parse(&meta.CCProblem)
+	if err != nil {
+		return nil, &Error{Stage: StageParse, Err: err}
+	}
+
+	var templateStr string
+	if i.opts.TemplatePath != "" {
+		templateStr, err = i.dm.LoadTemplate(i.opts.TemplatePath)
+		if err != nil {
+			return nil, &Error{Stage: StageTemplate, Err: err}
+		}
+	}
+
+	problemKey := directorymanager.NewProblem(parsedProblem, meta.ImportedAt)
+	if _, err := i.dm.ReadMetadata(problemKey); err != nil {
+		// Imported before, in a year a {{.Year}} layout placed it by.
+		if found, ok, err := i.dm.FindProblem(problemKey); err != nil {
+			return nil, &Error{Stage: directorymanager.StageDirectory, Err: err}
+		} else if ok {
+			problemKey = found
+		}
+	}
+
+	// A re-import refreshes the payload but keeps what was recorded locally.
+	if existing, err := i.dm.ReadMetadata(problemKey); err == nil {
+		existing.CCProblem = meta.CCProblem
+		meta = *existing
+	}
+
+	if i.opts.Lookup != nil && parsedProblem.Judge == "codeforces" {
+		if p := i.opts.Lookup(parsedProblem.ContestCode, parsedProblem.Index); p != nil {
+			meta.Tags, meta.Rating = p.Tags, p.Rating
+		}
+	}
+
+	err = i.dm.Import(problemKey, directorymanager.ImportRequest{
+		TestCases:    parsedProblem.TestCases,
+		InputPrefix:  i.opts.InputPrefix,
+		OutputPrefix: i.opts.OutputPrefix,
+		Policy:       i.opts.Policy,
+		ProgramFile:  i.opts.ProgramFile,
+		Template:     templateStr,
+		Metadata:     meta,
+	})
+	if err != nil {
+		var dmErr *directorymanager.ImportError
+		if errors.As(err, &dmErr) {
+			return nil, &Error{Stage: dmErr.Stage, Err: dmErr.Err}
+		}
+		return nil, err
+	}
+
+	return &Result{
+		Problem:     problemKey,
+		Dir:         i.dm.FullProblemPath(problemKey),
+		RelativeDir: i.dm.RelativeDir(problemKey),
+		ProgramFile: i.opts.ProgramFile,
+	}, nil
+}
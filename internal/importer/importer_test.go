@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+const samplePayload = `{
+  "name": "A. Sum of Two Numbers",
+  "url": "https://codeforces.com/contest/1234/problem/A",
+  "tests": [{"input": "1 2\n", "output": "3\n"}]
+}`
+
+func setupTestImporter(t *testing.T, templatePath string) (*Importer, *directorymanager.DirectoryManager) {
+	t.Helper()
+	logger := log.New(os.Stdout, "[test] ", log.Lshortfile)
+	dm := directorymanager.NewDirectoryManager(t.TempDir(), logger)
+	imp := NewImporter(dm, Options{
+		InputPrefix:  "input",
+		OutputPrefix: "output",
+		Policy:       directorymanager.ReimportOverwrite,
+		ProgramFile:  "main.cpp",
+		TemplatePath: templatePath,
+	}, logger)
+	return imp, dm
+}
+
+func TestImport(t *testing.T) {
+	imp, _ := setupTestImporter(t, "")
+
+	res, err := imp.Import([]byte(samplePayload))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if res.RelativeDir != filepath.Join("1234", "A_Sum_of_Two_Numbers") {
+		t.Errorf("unexpected relative dir %q", res.RelativeDir)
+	}
+	data, err := os.ReadFile(filepath.Join(res.Dir, "tests", "sample-input1"))
+	if err != nil || string(data) != "1 2" {
+		t.Errorf("sample not written: %q, %v", data, err)
+	}
+}
+
+func TestImport_Problemset(t *testing.T) {
+	imp, dm := setupTestImporter(t, "")
+	imp.opts.Lookup = func(contestID int, index string) *cfapi.Problem {
+		if contestID == 1234 && index == "A" {
+			return &cfapi.Problem{ContestID: 1234, Index: "A", Rating: 800, Tags: []string{"math"}}
+		}
+		return nil
+	}
+
+	res, err := imp.Import([]byte(samplePayload))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	meta, err := dm.ReadMetadata(res.Problem)
+	if err != nil {
+		t.Fatalf("ReadMetadata failed: %v", err)
+	}
+	if meta.Rating != 800 || len(meta.Tags) != 1 || meta.Tags[0] != "math" {
+		t.Errorf("expected the problemset data in problem.json, got rating %d and tags %v", meta.Rating, meta.Tags)
+	}
+}
+
+func TestImport_Replay(t *testing.T) {
+	imp, dm := setupTestImporter(t, "")
+
+	res, err := imp.Import([]byte(samplePayload))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	stored, err := os.ReadFile(filepath.Join(res.Dir, directorymanager.MetadataFile))
+	if err != nil {
+		t.Fatalf("reading problem.json failed: %v", err)
+	}
+	original, err := dm.ReadMetadata(res.Problem)
+	if err != nil {
+		t.Fatalf("ReadMetadata failed: %v", err)
+	}
+
+	if err := os.RemoveAll(res.Dir); err != nil {
+		t.Fatalf("removing problem failed: %v", err)
+	}
+
+	replayed, err := imp.Import(stored)
+	if err != nil {
+		t.Fatalf("replaying problem.json failed: %v", err)
+	}
+	if replayed.Dir != res.Dir {
+		t.Errorf("expected replay to recreate %s, got %s", res.Dir, replayed.Dir)
+	}
+	meta, err := dm.ReadMetadata(replayed.Problem)
+	if err != nil {
+		t.Fatalf("ReadMetadata failed: %v", err)
+	}
+	if !meta.ImportedAt.Equal(original.ImportedAt) {
+		t.Errorf("expected import time %v to be kept, got %v", original.ImportedAt, meta.ImportedAt)
+	}
+}
+
+func TestImport_YearLayout(t *testing.T) {
+	imp, dm := setupTestImporter(t, "")
+	if err := dm.SetLayout("{{.Year}}/{{.Contest}}{{.Index}}"); err != nil {
+		t.Fatalf("SetLayout failed: %v", err)
+	}
+
+	first, err := imp.Import([]byte(samplePayload))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	// Pretend the problem was imported in an earlier year.
+	err = dm.UpdateMetadata(first.Problem, func(meta *directorymanager.Metadata) {
+		meta.ImportedAt = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+	if err != nil {
+		t.Fatalf("UpdateMetadata failed: %v", err)
+	}
+	if _, err := dm.MigrateLayout("{{.Year}}/{{.Contest}}{{.Index}}", false); err != nil {
+		t.Fatalf("MigrateLayout failed: %v", err)
+	}
+
+	again, err := imp.Import([]byte(samplePayload))
+	if err != nil {
+		t.Fatalf("re-import failed: %v", err)
+	}
+	if again.RelativeDir != filepath.Join("2020", "1234A") {
+		t.Errorf("expected the re-import to reuse 2020/1234A, got %s", again.RelativeDir)
+	}
+	if _, err := os.Stat(first.Dir); !os.IsNotExist(err) {
+		t.Errorf("expected no duplicate in %s", first.Dir)
+	}
+}
+
+func TestImport_Stages(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      string
+		templatePath string
+		stage        string
+	}{
+		{"invalid json", `{"name":`, "", StageDecode},
+		{"unsupported url", `{"name":"A. X","url":"https://codeforces.com/gym"}`, "", StageParse},
+		{"missing template", samplePayload, "/does/not/exist.cpp", StageTemplate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp, _ := setupTestImporter(t, tt.templatePath)
+
+			_, err := imp.Import([]byte(tt.payload))
+			var stageErr *Error
+			if !errors.As(err, &stageErr) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if stageErr.Stage != tt.stage {
+				t.Errorf("expected stage %q, got %q", tt.stage, stageErr.Stage)
+			}
+		})
+	}
+}
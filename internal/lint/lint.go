@@ -0,0 +1,226 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/bundle"
+)
+
+// Finding is a problem found on a (1-based) line of a program.
+type Finding struct {
+	Rule    string
+	Line    int
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%d: [%s] %s", f.Line, f.Rule, f.Message)
+}
+
+// Rule checks the lines of a program, which are passed with comments blanked
+// out. For C++, lines inside #ifdef LOCAL blocks are marked in local.
+type Rule struct {
+	Name        string
+	Description string
+	languages   []string
+	check       func(lines []string, local []bool) []Finding
+}
+
+var cppLanguages = []string{"cpp", "cc", "cxx"}
+
+// Rules lists every rule in the order findings are reported.
+var Rules = []Rule{
+	{
+		Name:        "cerr",
+		Description: "debug output to cerr outside #ifdef LOCAL",
+		languages:   cppLanguages,
+		check:       matchOutsideLocal(regexp.MustCompile(`\bcerr\b`), "debug output to cerr outside #ifdef LOCAL"),
+	},
+	{
+		Name:        "freopen",
+		Description: "freopen of local files outside #ifdef LOCAL",
+		languages:   cppLanguages,
+		check:       matchOutsideLocal(regexp.MustCompile(`\bfreopen\s*\(`), "freopen outside #ifdef LOCAL redirects the judge's input or output"),
+	},
+	{
+		Name:        "int-scanf",
+		Description: "scanf or printf with %d while int is defined as long long",
+		languages:   cppLanguages,
+		check:       checkIntScanf,
+	},
+	{
+		Name:        "sync-with-stdio",
+		Description: "cin or cout without ios::sync_with_stdio(false)",
+		languages:   cppLanguages,
+		check:       checkSyncWithStdio,
+	},
+	{
+		Name:        "stderr-print",
+		Description: "leftover output to sys.stderr",
+		languages:   []string{"py"},
+		check:       matchOutsideLocal(regexp.MustCompile(`file\s*=\s*sys\.stderr|sys\.stderr\.write`), "leftover debug output to sys.stderr"),
+	},
+}
+
+// RuleNames returns the names of all rules.
+func RuleNames() []string {
+	names := make([]string, 0, len(Rules))
+	for _, r := range Rules {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// ParseRules returns the rules with the given names, or every rule when
+// names is empty.
+func ParseRules(names []string) ([]Rule, error) {
+	if len(names) == 0 {
+		return Rules, nil
+	}
+	var rules []Rule
+	for _, r := range Rules {
+		if slices.Contains(names, r.Name) {
+			rules = append(rules, r)
+		}
+	}
+	for _, name := range names {
+		if !slices.Contains(RuleNames(), name) {
+			return nil, fmt.Errorf("unknown lint rule %q (expected one of %s)", name, strings.Join(RuleNames(), ", "))
+		}
+	}
+	return rules, nil
+}
+
+// Lint runs the rules that apply to language on src.
+func Lint(language string, src []byte, rules []Rule) []Finding {
+	cpp := slices.Contains(cppLanguages, language)
+	if cpp {
+		src = bundle.StripCComments(src)
+	}
+	lines := strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")
+	if language == "py" {
+		for i, line := range lines {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				lines[i] = ""
+			}
+		}
+	}
+
+	local := make([]bool, len(lines))
+	if cpp {
+		local = localLines(lines)
+	}
+
+	var findings []Finding
+	for _, r := range rules {
+		if slices.Contains(r.languages, language) {
+			for _, f := range r.check(lines, local) {
+				f.Rule = r.Name
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings
+}
+
+var (
+	ifLocalRe    = regexp.MustCompile(`^\s*#\s*(ifdef\s+LOCAL\b|if\s+defined\s*\(?\s*LOCAL\b|ifndef\s+ONLINE_JUDGE\b)`)
+	ifNotLocalRe = regexp.MustCompile(`^\s*#\s*(ifndef\s+LOCAL\b|if\s*!\s*defined\s*\(?\s*LOCAL\b|ifdef\s+ONLINE_JUDGE\b)`)
+	ifRe         = regexp.MustCompile(`^\s*#\s*if`)
+	elseRe       = regexp.MustCompile(`^\s*#\s*(else|elif)\b`)
+	endifRe      = regexp.MustCompile(`^\s*#\s*endif\b`)
+)
+
+type ifKind int
+
+const (
+	ifOther    ifKind = iota
+	ifLocal           // #ifdef LOCAL: the first branch is local-only
+	ifNotLocal        // #ifndef LOCAL: the #else branch is local-only
+)
+
+type ifFrame struct {
+	kind   ifKind
+	inElse bool
+}
+
+func (f ifFrame) local() bool {
+	return f.kind == ifLocal && !f.inElse || f.kind == ifNotLocal && f.inElse
+}
+
+// localLines marks the lines only compiled locally: inside #ifdef LOCAL (or
+// #ifndef ONLINE_JUDGE), or in the #else branch of #ifndef LOCAL.
+func localLines(lines []string) []bool {
+	local := make([]bool, len(lines))
+
+	var stack []ifFrame
+	for i, line := range lines {
+		switch {
+		case ifLocalRe.MatchString(line):
+			stack = append(stack, ifFrame{kind: ifLocal})
+		case ifNotLocalRe.MatchString(line):
+			stack = append(stack, ifFrame{kind: ifNotLocal})
+		case ifRe.MatchString(line):
+			stack = append(stack, ifFrame{kind: ifOther})
+		case elseRe.MatchString(line) && len(stack) > 0:
+			stack[len(stack)-1].inElse = true
+		case endifRe.MatchString(line) && len(stack) > 0:
+			stack = stack[:len(stack)-1]
+		}
+		local[i] = slices.ContainsFunc(stack, ifFrame.local)
+	}
+	return local
+}
+
+func matchOutsideLocal(re *regexp.Regexp, message string) func([]string, []bool) []Finding {
+	return func(lines []string, local []bool) []Finding {
+		var findings []Finding
+		for i, line := range lines {
+			if !local[i] && re.MatchString(line) {
+				findings = append(findings, Finding{Line: i + 1, Message: message})
+			}
+		}
+		return findings
+	}
+}
+
+var (
+	defineIntRe = regexp.MustCompile(`^\s*#\s*define\s+int\s+long\s+long\b`)
+	scanfIntRe  = regexp.MustCompile(`\b(scanf|printf)\s*\(\s*"[^"]*%d`)
+	cinCoutRe   = regexp.MustCompile(`\b(cin|cout)\b`)
+	syncRe      = regexp.MustCompile(`sync_with_stdio\s*\(\s*(false|0)\s*\)`)
+)
+
+func checkIntScanf(lines []string, _ []bool) []Finding {
+	defined := false
+	var findings []Finding
+	for i, line := range lines {
+		if defineIntRe.MatchString(line) {
+			defined = true
+			continue
+		}
+		if defined && scanfIntRe.MatchString(line) {
+			findings = append(findings, Finding{Line: i + 1, Message: "%d with int defined as long long, use %lld"})
+		}
+	}
+	return findings
+}
+
+func checkSyncWithStdio(lines []string, local []bool) []Finding {
+	usesStreams := 0
+	for i, line := range lines {
+		if syncRe.MatchString(line) {
+			return nil
+		}
+		if usesStreams == 0 && !local[i] && cinCoutRe.MatchString(line) {
+			usesStreams = i + 1
+		}
+	}
+	if usesStreams == 0 {
+		return nil
+	}
+	return []Finding{{Line: usesStreams, Message: "cin/cout without ios::sync_with_stdio(false) may be too slow for large inputs"}}
+}
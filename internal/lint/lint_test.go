@@ -0,0 +1,85 @@
+package lint
+
+import (
+	"testing"
+)
+
+func findingRules(findings []Finding) map[string][]int {
+	rules := make(map[string][]int)
+	for _, f := range findings {
+		rules[f.Rule] = append(rules[f.Rule], f.Line)
+	}
+	return rules
+}
+
+func TestLint_CPP(t *testing.T) {
+	src := `#include <bits/stdc++.h>
+#define int long long
+#ifdef LOCAL
+#define dbg(x) cerr << #x << " = " << x << endl
+#else
+#define dbg(x)
+#endif
+#ifndef LOCAL
+#else
+void trace() { cerr << "local"; }
+#endif
+signed main() {
+    // cerr << "commented out";
+    freopen("input.txt", "r", stdin);
+    int n; scanf("%d", &n);
+    cerr << n;
+    cout << n << "\n";
+}
+`
+	rules, _ := ParseRules(nil)
+	got := findingRules(Lint("cpp", []byte(src), rules))
+
+	want := map[string][]int{
+		"cerr":            {16},
+		"freopen":         {14},
+		"int-scanf":       {15},
+		"sync-with-stdio": {17},
+	}
+	for rule, lines := range want {
+		if len(got[rule]) != len(lines) || got[rule][0] != lines[0] {
+			t.Errorf("%s: expected findings on lines %v, got %v", rule, lines, got[rule])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("unexpected findings: %v", got)
+	}
+}
+
+func TestLint_CleanCPP(t *testing.T) {
+	src := `#include <bits/stdc++.h>
+int main() {
+    std::ios::sync_with_stdio(false);
+    std::cin.tie(nullptr);
+    int n; std::cin >> n; std::cout << n;
+}
+`
+	rules, _ := ParseRules(nil)
+	if findings := Lint("cpp", []byte(src), rules); len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+}
+
+func TestLint_Python(t *testing.T) {
+	src := "import sys\n# print(1, file=sys.stderr)\nprint(2, file=sys.stderr)\nsys.stderr.write('x')\nprint(3)\n"
+	rules, _ := ParseRules([]string{"stderr-print", "cerr"})
+	got := findingRules(Lint("py", []byte(src), rules))
+	if lines := got["stderr-print"]; len(lines) != 2 || lines[0] != 3 || lines[1] != 4 {
+		t.Errorf("expected stderr-print on lines 3 and 4, got %v", got)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]string{"freopen"})
+	if err != nil || len(rules) != 1 || rules[0].Name != "freopen" {
+		t.Errorf("expected only freopen, got %v (err %v)", rules, err)
+	}
+	if _, err := ParseRules([]string{"tabs"}); err == nil {
+		t.Errorf("expected unknown rule to fail")
+	}
+}
@@ -0,0 +1,90 @@
+package listing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+// RatingRange is an inclusive range of problem ratings. A zero bound is
+// open.
+type RatingRange struct {
+	Min, Max int
+}
+
+// ParseRatingRange parses "1600-1900", "1600" (exactly 1600), "1600-" or
+// "-1900".
+func ParseRatingRange(s string) (RatingRange, error) {
+	bound := func(b string) (int, error) {
+		if b == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(b)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid rating range %q", s)
+		}
+		return n, nil
+	}
+
+	lo, hi, found := strings.Cut(strings.TrimSpace(s), "-")
+	if !found {
+		hi = lo
+	}
+	if lo == "" && hi == "" {
+		return RatingRange{}, fmt.Errorf("invalid rating range %q", s)
+	}
+	min, err := bound(lo)
+	if err != nil {
+		return RatingRange{}, err
+	}
+	max, err := bound(hi)
+	if err != nil {
+		return RatingRange{}, err
+	}
+	if max > 0 && min > max {
+		return RatingRange{}, fmt.Errorf("invalid rating range %q: %d is above %d", s, min, max)
+	}
+	return RatingRange{Min: min, Max: max}, nil
+}
+
+// Contains reports whether rating is in the range. An unknown rating (0)
+// is only in the unbounded range.
+func (r RatingRange) Contains(rating int) bool {
+	if r == (RatingRange{}) {
+		return true
+	}
+	return rating > 0 && rating >= r.Min && (r.Max == 0 || rating <= r.Max)
+}
+
+// Filter selects problems. Zero fields match everything.
+type Filter struct {
+	ContestID int
+	Tags      []string // all must be present, ignoring case
+	Rating    RatingRange
+}
+
+// Match reports whether a problem with the given tags and rating passes the
+// filter.
+func (f Filter) Match(p directorymanager.Problem, tags []string, rating int) bool {
+	if f.ContestID != 0 && p.ContestCode != f.ContestID {
+		return false
+	}
+	if !f.Rating.Contains(rating) {
+		return false
+	}
+	for _, want := range f.Tags {
+		found := false
+		for _, tag := range tags {
+			if strings.EqualFold(tag, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package listing
+
+import (
+	"testing"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+func TestParseRatingRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want RatingRange
+	}{
+		{"1600-1900", RatingRange{1600, 1900}},
+		{"1600", RatingRange{1600, 1600}},
+		{"1600-", RatingRange{1600, 0}},
+		{"-1900", RatingRange{0, 1900}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRatingRange(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRatingRange(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "-", "hard", "1900-1600", "0-100"} {
+		if _, err := ParseRatingRange(in); err == nil {
+			t.Errorf("expected ParseRatingRange(%q) to fail", in)
+		}
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	p := directorymanager.Problem{ContestCode: 1985, Index: "C"}
+	tags := []string{"dp", "greedy"}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		rating int
+		want   bool
+	}{
+		{"empty", Filter{}, 0, true},
+		{"tags ignore case", Filter{Tags: []string{"DP", "greedy"}}, 1700, true},
+		{"missing tag", Filter{Tags: []string{"dp", "graphs"}}, 1700, false},
+		{"in range", Filter{Rating: RatingRange{1600, 1900}}, 1900, true},
+		{"above range", Filter{Rating: RatingRange{1600, 1900}}, 2000, false},
+		{"unknown rating", Filter{Rating: RatingRange{0, 1900}}, 0, false},
+		{"other contest", Filter{ContestID: 1986}, 1700, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(p, tags, tt.rating); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
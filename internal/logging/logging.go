@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+func ParseLevel(s string) (Level, error) {
+	for level, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", s)
+}
+
+// Logger writes leveled messages to a console writer, normally stderr, and
+// optionally to a log file. The console only shows messages at or above the
+// configured level; the log file receives every message.
+type Logger struct {
+	mu      sync.Mutex
+	console io.Writer
+	file    io.Writer
+	level   Level
+}
+
+func New(console io.Writer, level Level) *Logger {
+	return &Logger{
+		console: console,
+		level:   level,
+	}
+}
+
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetFile sends a timestamped copy of every message to w; nil disables it.
+func (l *Logger) SetFile(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.file = w
+}
+
+func (l *Logger) Debugf(format string, args ...any) { l.log(LevelDebug, fmt.Sprintf(format, args...)) }
+func (l *Logger) Infof(format string, args ...any)  { l.log(LevelInfo, fmt.Sprintf(format, args...)) }
+func (l *Logger) Warnf(format string, args ...any)  { l.log(LevelWarn, fmt.Sprintf(format, args...)) }
+func (l *Logger) Errorf(format string, args ...any) { l.log(LevelError, fmt.Sprintf(format, args...)) }
+
+func (l *Logger) log(level Level, msg string) {
+	msg = strings.TrimRight(msg, "\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		fmt.Fprintf(l.file, "%s %-5s %s\n", time.Now().Format("2006/01/02 15:04:05"), level, msg)
+	}
+	if level < l.level {
+		return
+	}
+	if level == LevelInfo {
+		fmt.Fprintln(l.console, msg)
+	} else {
+		fmt.Fprintf(l.console, "%s: %s\n", level, msg)
+	}
+}
+
+// Std returns a *log.Logger for the internal packages. Their lines are logged
+// at debug level unless they start with the "ERROR:" or "WARN:" markers those
+// packages already use, which are logged at error and warn level instead.
+func (l *Logger) Std() *log.Logger {
+	return log.New(stdWriter{l}, "", 0)
+}
+
+type stdWriter struct {
+	l *Logger
+}
+
+func (w stdWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		level := LevelDebug
+		for _, marker := range []Level{LevelError, LevelWarn} {
+			prefix := marker.String() + ":"
+			if rest, ok := strings.CutPrefix(line, prefix); ok {
+				level, line = marker, strings.TrimSpace(rest)
+				break
+			}
+		}
+		w.l.log(level, line)
+	}
+	return len(p), nil
+}
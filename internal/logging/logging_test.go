@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLogger_Levels(t *testing.T) {
+	var console, file bytes.Buffer
+	l := New(&console, LevelInfo)
+	l.SetFile(&file)
+
+	l.Debugf("hidden %d", 1)
+	l.Infof("shown %d", 2)
+	l.Warnf("careful")
+	l.Errorf("broken")
+
+	expected := "shown 2\nWARN: careful\nERROR: broken\n"
+	if console.String() != expected {
+		t.Errorf("expected console %q, got %q", expected, console.String())
+	}
+	if !strings.Contains(file.String(), "DEBUG hidden 1") {
+		t.Errorf("expected log file to receive debug messages, got %q", file.String())
+	}
+
+	console.Reset()
+	l.SetLevel(LevelError)
+	l.Warnf("quiet")
+	if console.Len() != 0 {
+		t.Errorf("expected warning to be suppressed, got %q", console.String())
+	}
+}
+
+func TestLogger_Std(t *testing.T) {
+	var console bytes.Buffer
+	l := New(&console, LevelWarn)
+	std := l.Std()
+
+	std.Printf("Ensuring directory: /tmp")
+	std.Printf("WARN: invalid trailing test case number: input")
+	std.Printf("ERROR: Failed to execute test %d\n", 3)
+
+	expected := "WARN: invalid trailing test case number: input\nERROR: Failed to execute test 3\n"
+	if console.String() != expected {
+		t.Errorf("expected %q, got %q", expected, console.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if level, err := ParseLevel("warn"); err != nil || level != LevelWarn {
+		t.Errorf("expected warn, got %v, %v", level, err)
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Errorf("expected error for unknown level")
+	}
+}
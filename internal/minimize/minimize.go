@@ -0,0 +1,163 @@
+package minimize
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Granularity is what delta debugging removes from an input.
+type Granularity string
+
+const (
+	ByLines  Granularity = "lines"
+	ByTokens Granularity = "tokens"
+)
+
+func ParseGranularity(s string) (Granularity, error) {
+	switch g := Granularity(s); g {
+	case ByLines, ByTokens:
+		return g, nil
+	}
+	return "", fmt.Errorf("invalid granularity %q (expected lines or tokens)", s)
+}
+
+// unit is a line, or a token with the line it is on.
+type unit struct {
+	line int
+	text string
+}
+
+func split(input string, g Granularity) []unit {
+	var units []unit
+	for i, line := range strings.Split(strings.TrimRight(input, "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if g == ByLines {
+			if strings.TrimSpace(line) != "" {
+				units = append(units, unit{line: i, text: line})
+			}
+			continue
+		}
+		for _, token := range strings.Fields(line) {
+			units = append(units, unit{line: i, text: token})
+		}
+	}
+	return units
+}
+
+// join rebuilds an input from units, keeping the tokens left on each line
+// together and dropping emptied lines.
+func join(units []unit) string {
+	var b strings.Builder
+	for i, u := range units {
+		if i > 0 {
+			if u.line == units[i-1].line {
+				b.WriteByte(' ')
+			} else {
+				b.WriteByte('\n')
+			}
+		}
+		b.WriteString(u.text)
+	}
+	if len(units) > 0 {
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Minimizer shrinks an input while it stays failing.
+type Minimizer struct {
+	fails    func(input string) (bool, error)
+	progress func(input string)
+	tried    map[string]bool
+	Tests    int // inputs tested, without repeats
+}
+
+// New returns a Minimizer keeping inputs for which fails holds. fails is
+// assumed to hold for the input being minimized.
+func New(fails func(input string) (bool, error)) *Minimizer {
+	return &Minimizer{fails: fails, tried: map[string]bool{}}
+}
+
+// SetProgress calls fn with every smaller failing input found.
+func (m *Minimizer) SetProgress(fn func(input string)) {
+	m.progress = fn
+}
+
+func (m *Minimizer) test(units []unit) (bool, error) {
+	input := join(units)
+	if failed, ok := m.tried[input]; ok {
+		return failed, nil
+	}
+	m.Tests++
+	failed, err := m.fails(input)
+	if err != nil {
+		return false, err
+	}
+	m.tried[input] = failed
+	if failed && m.progress != nil {
+		m.progress(input)
+	}
+	return failed, nil
+}
+
+// Minimize runs delta debugging (ddmin) over the lines or tokens of input,
+// returning a failing input from which no single line or token can be
+// removed without it passing.
+func (m *Minimizer) Minimize(input string, g Granularity) (string, error) {
+	units := split(input, g)
+	n := 2
+	for len(units) >= 2 {
+		chunks := chunk(units, n)
+		reduced := false
+
+		for _, c := range chunks {
+			failed, err := m.test(c)
+			if err != nil {
+				return "", err
+			}
+			if failed {
+				units, n, reduced = c, 2, true
+				break
+			}
+		}
+
+		if !reduced {
+			for i := range chunks {
+				complement := make([]unit, 0, len(units))
+				for j, c := range chunks {
+					if j != i {
+						complement = append(complement, c...)
+					}
+				}
+				failed, err := m.test(complement)
+				if err != nil {
+					return "", err
+				}
+				if failed {
+					units, n, reduced = complement, max(n-1, 2), true
+					break
+				}
+			}
+		}
+
+		if !reduced {
+			if n >= len(units) {
+				break
+			}
+			n = min(2*n, len(units))
+		}
+	}
+	return join(units), nil
+}
+
+// chunk splits units into n parts of nearly equal size.
+func chunk(units []unit, n int) [][]unit {
+	chunks := make([][]unit, 0, n)
+	start := 0
+	for i := 0; i < n; i++ {
+		end := start + (len(units)-start)/(n-i)
+		chunks = append(chunks, units[start:end])
+		start = end
+	}
+	return chunks
+}
@@ -0,0 +1,63 @@
+package minimize
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMinimize_Lines(t *testing.T) {
+	// Fails while both "3" and "7" are in the input.
+	input := "1\n2\n3\n4\n5\n6\n7\n8\n"
+	m := New(func(in string) (bool, error) {
+		return strings.Contains(in, "3\n") && strings.Contains(in, "7\n"), nil
+	})
+	var steps []string
+	m.SetProgress(func(in string) { steps = append(steps, in) })
+
+	got, err := m.Minimize(input, ByLines)
+	if err != nil {
+		t.Fatalf("Minimize failed: %v", err)
+	}
+	if got != "3\n7\n" {
+		t.Errorf("Minimize = %q, want %q", got, "3\n7\n")
+	}
+	if len(steps) == 0 || steps[len(steps)-1] != got {
+		t.Errorf("expected progress to end with the result, got %q", steps)
+	}
+}
+
+func TestMinimize_Tokens(t *testing.T) {
+	// Fails while the array holds a negative number.
+	input := "5\n4 -2 8 1 9\n"
+	m := New(func(in string) (bool, error) {
+		return strings.Contains(in, "-"), nil
+	})
+
+	got, err := m.Minimize(input, ByTokens)
+	if err != nil {
+		t.Fatalf("Minimize failed: %v", err)
+	}
+	if got != "-2\n" {
+		t.Errorf("Minimize = %q, want %q", got, "-2\n")
+	}
+
+	// Tokens stay on their lines.
+	m = New(func(in string) (bool, error) {
+		return strings.Contains(in, "5") && strings.Contains(in, "9"), nil
+	})
+	if got, _ := m.Minimize(input, ByTokens); got != "5\n9\n" {
+		t.Errorf("Minimize = %q, want %q", got, "5\n9\n")
+	}
+}
+
+func TestMinimize_Error(t *testing.T) {
+	broken := errors.New("brute crashed")
+	m := New(func(in string) (bool, error) { return false, broken })
+	if _, err := m.Minimize("1\n2\n", ByLines); !errors.Is(err, broken) {
+		t.Errorf("expected the error of fails, got %v", err)
+	}
+	if _, err := ParseGranularity("chars"); err == nil {
+		t.Errorf("expected an unknown granularity to fail")
+	}
+}
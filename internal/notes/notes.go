@@ -0,0 +1,116 @@
+package notes
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileName is the name of the notes file in a problem directory.
+const FileName = "NOTES.md"
+
+// Info is what the notes of a problem are prefilled with. Zero fields are
+// left out.
+type Info struct {
+	Name          string
+	URL           string
+	TimeLimitMs   int
+	MemoryLimitMB int
+	Tags          []string
+	Rating        int
+}
+
+// Template returns the initial content of the notes of a problem.
+func Template(info Info) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", info.Name)
+	if info.URL != "" {
+		fmt.Fprintf(&b, "- Problem: %s\n", info.URL)
+		if contest := ContestURL(info.URL); contest != "" {
+			fmt.Fprintf(&b, "- Contest: %s (editorial under Contest materials)\n", contest)
+		}
+	}
+	if info.TimeLimitMs > 0 || info.MemoryLimitMB > 0 {
+		fmt.Fprintf(&b, "- Limits: %d ms, %d MB\n", info.TimeLimitMs, info.MemoryLimitMB)
+	}
+	if info.Rating > 0 {
+		fmt.Fprintf(&b, "- Rating: %d\n", info.Rating)
+	}
+	if len(info.Tags) > 0 {
+		fmt.Fprintf(&b, "- Tags: %s\n", strings.Join(info.Tags, ", "))
+	}
+	b.WriteString("\n## Idea\n\n\n## Pitfalls\n\n\n## Editorial\n\n")
+	return b.String()
+}
+
+var problemURLRe = regexp.MustCompile(`^(.*)/(?:(contest|gym)/(\d+)|problemset)/problem/(?:(\d+)/)?\w+/?$`)
+
+// ContestURL returns the page of the contest of a Codeforces problem, which
+// links to its editorial, or "" for other URLs.
+func ContestURL(problemURL string) string {
+	m := problemURLRe.FindStringSubmatch(problemURL)
+	switch {
+	case m == nil:
+		return ""
+	case m[2] != "":
+		return fmt.Sprintf("%s/%s/%s", m[1], m[2], m[3])
+	case m[4] != "":
+		return fmt.Sprintf("%s/contest/%s", m[1], m[4])
+	}
+	return ""
+}
+
+// Match is a line of a notes file containing the searched text.
+type Match struct {
+	Path string
+	Line int // 1-based
+	Text string
+}
+
+// Search finds the lines of the notes below root containing query,
+// ignoring case.
+func Search(root, query string) ([]Match, error) {
+	query = strings.ToLower(query)
+	var matches []Match
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() != FileName {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for line := 1; scanner.Scan(); line++ {
+			if strings.Contains(strings.ToLower(scanner.Text()), query) {
+				matches = append(matches, Match{Path: path, Line: line, Text: scanner.Text()})
+			}
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching notes: %w", err)
+	}
+	return matches, nil
+}
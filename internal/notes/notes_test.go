@@ -0,0 +1,82 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	got := Template(Info{
+		Name:          "C. Good Prefixes",
+		URL:           "https://codeforces.com/contest/1985/problem/C",
+		TimeLimitMs:   2000,
+		MemoryLimitMB: 256,
+		Tags:          []string{"greedy", "implementation"},
+		Rating:        1000,
+	})
+	for _, want := range []string{
+		"# C. Good Prefixes\n",
+		"- Problem: https://codeforces.com/contest/1985/problem/C\n",
+		"- Contest: https://codeforces.com/contest/1985 ",
+		"- Limits: 2000 ms, 256 MB\n",
+		"- Rating: 1000\n",
+		"- Tags: greedy, implementation\n",
+		"## Idea",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the template to contain %q:\n%s", want, got)
+		}
+	}
+
+	if got := Template(Info{Name: "Local problem"}); strings.Contains(got, "- ") {
+		t.Errorf("expected unknown fields to be left out:\n%s", got)
+	}
+}
+
+func TestContestURL(t *testing.T) {
+	tests := map[string]string{
+		"https://codeforces.com/contest/1985/problem/C":     "https://codeforces.com/contest/1985",
+		"https://codeforces.com/gym/104000/problem/B2":      "https://codeforces.com/gym/104000",
+		"https://codeforces.com/problemset/problem/1700/C":  "https://codeforces.com/contest/1700",
+		"https://atcoder.jp/contests/abc300/tasks/abc300_a": "",
+	}
+	for url, want := range tests {
+		if got := ContestURL(url); got != want {
+			t.Errorf("ContestURL(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("1985/C/NOTES.md", "# C\n\n## Idea\nLazy Segment Tree over prefix sums\n")
+	write("1985/D/NOTES.md", "# D\n\nsegment tree beats was overkill\n")
+	write("1985/E/main.cpp", "// segment tree\n")
+	write(".git/NOTES.md", "segment tree\n")
+
+	matches, err := Search(root, "segment tree")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %+v", matches)
+	}
+	if matches[0].Path != filepath.Join(root, "1985/C/NOTES.md") || matches[0].Line != 4 || matches[0].Text != "Lazy Segment Tree over prefix sums" {
+		t.Errorf("unexpected match: %+v", matches[0])
+	}
+
+	if matches, err := Search(filepath.Join(root, "missing"), "x"); err != nil || len(matches) != 0 {
+		t.Errorf("expected no matches below a missing root, got %v, %v", matches, err)
+	}
+}
@@ -0,0 +1,275 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+)
+
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+	FormatTAP   Format = "tap"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatJSON, FormatJUnit, FormatTAP:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid format %q (expected text, json, junit or tap)", s)
+}
+
+// Write renders results in one of the machine-readable formats. The problem
+// names the suite the results belong to, e.g. "1234A". Text output is left
+// to the caller, which colours it for the terminal.
+func Write(w io.Writer, format Format, problem string, results []execution.Result) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, problem, results)
+	case FormatJUnit:
+		return writeJUnit(w, problem, results)
+	case FormatTAP:
+		return writeTAP(w, results)
+	}
+	return fmt.Errorf("format %q is not machine-readable", format)
+}
+
+type jsonReport struct {
+	Problem string     `json:"problem"`
+	Verdict string     `json:"verdict,omitempty"` // CE when the build failed
+	Error   string     `json:"error,omitempty"`
+	Passed  int        `json:"passed"`
+	Failed  int        `json:"failed"`
+	Total   int        `json:"total"`
+	Tests   []jsonTest `json:"tests"`
+}
+
+type jsonTest struct {
+	Name     string            `json:"name"`
+	Group    string            `json:"group,omitempty"`
+	Number   int               `json:"number"`
+	Verdict  execution.Verdict `json:"verdict"`
+	TimeMs   int64             `json:"timeMs"`
+	Memory   int64             `json:"memoryBytes"`
+	Expected string            `json:"expected"`
+	Actual   string            `json:"actual"`
+	Diff     string            `json:"diff,omitempty"`
+	Cases    *jsonSplit        `json:"cases,omitempty"`
+}
+
+// jsonSplit lists the cases of a failed multi-case test that failed on
+// their own.
+type jsonSplit struct {
+	Total  int           `json:"total"`
+	Failed []jsonSubcase `json:"failed"`
+}
+
+type jsonSubcase struct {
+	Index    int               `json:"index"`
+	Verdict  execution.Verdict `json:"verdict"`
+	Input    string            `json:"input"`
+	Expected string            `json:"expected"`
+	Actual   string            `json:"actual"`
+}
+
+func writeJSON(w io.Writer, problem string, results []execution.Result) error {
+	rep := jsonReport{Problem: problem, Total: len(results), Tests: []jsonTest{}}
+	for _, r := range results {
+		if r.Ok {
+			rep.Passed++
+		} else {
+			rep.Failed++
+		}
+		test := jsonTest{
+			Name:     r.Name(),
+			Group:    r.Group,
+			Number:   r.TestCase,
+			Verdict:  r.Verdict,
+			TimeMs:   r.Time.Milliseconds(),
+			Memory:   r.Memory,
+			Expected: r.ExpectedOutput,
+			Actual:   r.ProgramOutput,
+			Diff:     diff(r),
+		}
+		if r.Split != nil {
+			test.Cases = &jsonSplit{Total: r.Split.Total, Failed: []jsonSubcase{}}
+			for _, sub := range r.Split.Failed {
+				test.Cases.Failed = append(test.Cases.Failed, jsonSubcase{
+					Index:    sub.Index,
+					Verdict:  sub.Verdict,
+					Input:    sub.Input,
+					Expected: sub.ExpectedOutput,
+					Actual:   sub.ProgramOutput,
+				})
+			}
+		}
+		rep.Tests = append(rep.Tests, test)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// WriteBuildFailure renders a compile error in one of the machine-readable
+// formats, as a single failed "build" test with a CE verdict, so consumers
+// still get a document when no test could run.
+func WriteBuildFailure(w io.Writer, format Format, problem string, buildErr error) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(jsonReport{Problem: problem, Verdict: string(execution.VerdictCompileError), Error: buildErr.Error(), Tests: []jsonTest{}})
+	case FormatJUnit:
+		suite := junitSuite{Name: problem, Tests: 1, Errors: 1, Time: "0.000", Cases: []junitCase{{
+			Name:      "build",
+			ClassName: problem,
+			Time:      "0.000",
+			Error:     &junitFailure{Message: buildErr.Error(), Type: string(execution.VerdictCompileError)},
+		}}}
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	case FormatTAP:
+		_, err := fmt.Fprintf(w, "TAP version 13\n1..1\nnot ok 1 - build\n  ---\n  verdict: %s\n  error: %q\n  ...\n", execution.VerdictCompileError, buildErr.Error())
+		return err
+	}
+	return fmt.Errorf("format %q is not machine-readable", format)
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnit reports wrong answers as failures and runtime errors or
+// timeouts as errors, the way test runners distinguish them.
+func writeJUnit(w io.Writer, problem string, results []execution.Result) error {
+	suite := junitSuite{Name: problem, Tests: len(results)}
+	var total float64
+	for _, r := range results {
+		total += r.Time.Seconds()
+		tc := junitCase{
+			Name:      r.Name(),
+			ClassName: problem,
+			Time:      fmt.Sprintf("%.3f", r.Time.Seconds()),
+		}
+		if !r.Ok {
+			failure := &junitFailure{
+				Message: fmt.Sprintf("%s: %s", r.Verdict, diff(r)),
+				Type:    string(r.Verdict),
+				Body:    fmt.Sprintf("Expected Output:\n%s\nProgram Output:\n%s", r.ExpectedOutput, r.ProgramOutput),
+			}
+			if r.Verdict == execution.VerdictWrongAnswer {
+				tc.Failure = failure
+				suite.Failures++
+			} else {
+				tc.Error = failure
+				suite.Errors++
+			}
+			tc.SystemOut = r.ProgramOutput
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeTAP writes TAP version 13, with a YAML diagnostic block for every
+// failed test.
+func writeTAP(w io.Writer, results []execution.Result) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(results))
+	for i, r := range results {
+		if r.Ok {
+			fmt.Fprintf(&b, "ok %d - %s\n", i+1, r.Name())
+			continue
+		}
+		fmt.Fprintf(&b, "not ok %d - %s\n", i+1, r.Name())
+		b.WriteString("  ---\n")
+		fmt.Fprintf(&b, "  verdict: %s\n", r.Verdict)
+		fmt.Fprintf(&b, "  timeMs: %d\n", r.Time.Milliseconds())
+		fmt.Fprintf(&b, "  memoryBytes: %d\n", r.Memory)
+		if d := diff(r); d != "" {
+			fmt.Fprintf(&b, "  diff: %q\n", d)
+		}
+		writeYAMLBlock(&b, "expected", r.ExpectedOutput)
+		writeYAMLBlock(&b, "actual", r.ProgramOutput)
+		b.WriteString("  ...\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeYAMLBlock(b *strings.Builder, key, value string) {
+	fmt.Fprintf(b, "  %s: |-\n", key)
+	for _, line := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+		fmt.Fprintf(b, "    %s\n", line)
+	}
+}
+
+// diff summarises a failed result; only wrong answers have a meaningful
+// output diff.
+func diff(r execution.Result) string {
+	switch r.Verdict {
+	case execution.VerdictWrongAnswer:
+		return execution.DiffSummary(r.ExpectedOutput, r.ProgramOutput)
+	case execution.VerdictRuntimeError:
+		return "runtime error"
+	case execution.VerdictTimeLimit:
+		return "time limit exceeded"
+	}
+	return ""
+}
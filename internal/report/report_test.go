@@ -0,0 +1,130 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/execution"
+)
+
+func sampleResults() []execution.Result {
+	return []execution.Result{
+		{Ok: true, TestCase: 1, Group: "sample", Verdict: execution.VerdictOK, Time: 15 * time.Millisecond, ExpectedOutput: "3\n", ProgramOutput: "3\n"},
+		{TestCase: 2, Group: "sample", Verdict: execution.VerdictWrongAnswer, Time: 20 * time.Millisecond, ExpectedOutput: "1\n2\n", ProgramOutput: "1\n3\n",
+			Split: &execution.Split{Total: 2, Failed: []execution.Subcase{{Index: 2, Verdict: execution.VerdictWrongAnswer, Input: "1\n2\n", ExpectedOutput: "2\n", ProgramOutput: "3\n"}}}},
+		{TestCase: 1, Group: "custom", Verdict: execution.VerdictRuntimeError, ExpectedOutput: "5\n"},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"", "text", "json", "junit", "tap"} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q) failed: %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("expected ParseFormat(\"xml\") to fail")
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, "1234A", sampleResults()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var rep jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &rep); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if rep.Passed != 1 || rep.Failed != 2 || rep.Total != 3 {
+		t.Errorf("unexpected counts: %+v", rep)
+	}
+	wa := rep.Tests[1]
+	if wa.Name != "sample-2" || wa.Verdict != execution.VerdictWrongAnswer || wa.TimeMs != 20 {
+		t.Errorf("unexpected test entry: %+v", wa)
+	}
+	if !strings.Contains(wa.Diff, "line 2") {
+		t.Errorf("expected diff to point at line 2, got %q", wa.Diff)
+	}
+	if wa.Cases == nil || wa.Cases.Total != 2 || len(wa.Cases.Failed) != 1 || wa.Cases.Failed[0].Input != "1\n2\n" {
+		t.Errorf("unexpected failing cases: %+v", wa.Cases)
+	}
+	if rep.Tests[0].Cases != nil {
+		t.Errorf("expected no cases for an unsplit test")
+	}
+}
+
+func TestWriteBuildFailure(t *testing.T) {
+	buildErr := errors.New("build failed: exit status 1")
+
+	var buf bytes.Buffer
+	if err := WriteBuildFailure(&buf, FormatJSON, "1234A", buildErr); err != nil {
+		t.Fatalf("WriteBuildFailure failed: %v", err)
+	}
+	var rep jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &rep); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if rep.Verdict != "CE" || rep.Error != buildErr.Error() || rep.Total != 0 {
+		t.Errorf("unexpected report: %+v", rep)
+	}
+
+	buf.Reset()
+	if err := WriteBuildFailure(&buf, FormatJUnit, "1234A", buildErr); err != nil {
+		t.Fatalf("WriteBuildFailure failed: %v", err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if suite := suites.Suites[0]; suite.Errors != 1 || suite.Cases[0].Error == nil || suite.Cases[0].Error.Type != "CE" {
+		t.Errorf("unexpected suite: %+v", suite)
+	}
+
+	buf.Reset()
+	if err := WriteBuildFailure(&buf, FormatTAP, "1234A", buildErr); err != nil {
+		t.Fatalf("WriteBuildFailure failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "not ok 1 - build") || !strings.Contains(buf.String(), "verdict: CE") {
+		t.Errorf("unexpected TAP output:\n%s", buf.String())
+	}
+}
+
+func TestWrite_JUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJUnit, "1234A", sampleResults()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	suite := suites.Suites[0]
+	if suite.Tests != 3 || suite.Failures != 1 || suite.Errors != 1 {
+		t.Errorf("unexpected suite counts: tests=%d failures=%d errors=%d", suite.Tests, suite.Failures, suite.Errors)
+	}
+	if suite.Cases[1].Failure == nil || suite.Cases[2].Error == nil {
+		t.Errorf("expected WA as failure and RE as error")
+	}
+}
+
+func TestWrite_TAP(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatTAP, "1234A", sampleResults()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"1..3\n", "ok 1 - sample-1\n", "not ok 2 - sample-2\n", "  verdict: WA\n", "not ok 3 - custom-1\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected TAP output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
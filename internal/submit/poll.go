@@ -0,0 +1,84 @@
+package submit
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+)
+
+// Poller waits for the verdict of a submission using the Codeforces API.
+type Poller struct {
+	api      *cfapi.Client
+	interval time.Duration
+	timeout  time.Duration
+	logger   *log.Logger
+	progress func(*cfapi.Submission)
+
+	sleep func(time.Duration) // replaced in tests
+}
+
+func NewPoller(api *cfapi.Client, interval, timeout time.Duration, logger *log.Logger) *Poller {
+	return &Poller{
+		api:      api,
+		interval: interval,
+		timeout:  timeout,
+		logger:   logger,
+		sleep:    time.Sleep,
+	}
+}
+
+// SetProgress calls fn with the submission after every poll, as long as it is
+// listed, so callers can show how far the testing got.
+func (p *Poller) SetProgress(fn func(*cfapi.Submission)) {
+	p.progress = fn
+}
+
+// Final reports whether a verdict is final rather than queued or testing.
+func Final(verdict string) bool {
+	return verdict != "" && verdict != "TESTING"
+}
+
+// Wait polls the latest submissions of handle (user.status) until the
+// submission with the given id has a final verdict. A failed poll is only
+// logged, so polling goes on until the timeout.
+func (p *Poller) Wait(handle string, id int64) (*cfapi.Submission, error) {
+	deadline := time.Now().Add(p.timeout)
+	var last *cfapi.Submission
+	for {
+		sub, err := p.find(handle, id)
+		if err != nil {
+			p.logger.Printf("WARN: checking the verdict failed: %v", err)
+		} else if sub != nil {
+			last = sub
+			if p.progress != nil {
+				p.progress(sub)
+			}
+			if Final(sub.Verdict) {
+				return sub, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return last, fmt.Errorf("no verdict for submission %d after %s: %w", id, p.timeout, err)
+			}
+			return last, fmt.Errorf("no verdict for submission %d after %s", id, p.timeout)
+		}
+		p.sleep(p.interval)
+	}
+}
+
+func (p *Poller) find(handle string, id int64) (*cfapi.Submission, error) {
+	subs, err := p.api.UserStatus(handle, 1, 10)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		if subs[i].ID == id {
+			return &subs[i], nil
+		}
+	}
+	p.logger.Printf("Submission %d is not listed yet", id)
+	return nil, nil
+}
@@ -0,0 +1,329 @@
+package submit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Request is a solution to submit.
+type Request struct {
+	Target   Target
+	Language string // file extension of the source, e.g. "cpp"
+	Source   []byte
+}
+
+// Submitter sends solutions to a judge.
+type Submitter interface {
+	// Submit sends the solution and returns the id of the new submission.
+	Submit(req Request) (int64, error)
+}
+
+// Target identifies the problem a solution is submitted to.
+type Target struct {
+	Kind      string // "contest", "gym" or "problemset"
+	ContestID int
+	Index     string
+}
+
+var problemURLRe = regexp.MustCompile(`/(contest|gym)/(\d+)/problem/(\w+)|/problemset/problem/(\d+)/(\w+)`)
+
+// ParseTarget extracts the target from a problem URL such as
+// https://codeforces.com/contest/1234/problem/A.
+func ParseTarget(problemURL string) (Target, error) {
+	m := problemURLRe.FindStringSubmatch(problemURL)
+	if m == nil {
+		return Target{}, fmt.Errorf("%q is not a Codeforces problem URL", problemURL)
+	}
+	if m[1] != "" {
+		id, _ := strconv.Atoi(m[2])
+		return Target{Kind: m[1], ContestID: id, Index: m[3]}, nil
+	}
+	id, _ := strconv.Atoi(m[4])
+	return Target{Kind: "problemset", ContestID: id, Index: m[5]}, nil
+}
+
+// DefaultLanguageIDs maps file extensions to the Codeforces compiler
+// ("programTypeId") used for them.
+var DefaultLanguageIDs = map[string]string{
+	"c":    "43", // GNU GCC C11 5.1.0
+	"cpp":  "89", // GNU G++20 13.2 (64 bit, winlibs)
+	"cc":   "89",
+	"cxx":  "89",
+	"py":   "31", // Python 3.8.10
+	"java": "87", // Java 21 64bit
+	"kt":   "88", // Kotlin 1.9.21
+	"rs":   "75", // Rust 1.75.0 (2021)
+	"go":   "32", // Go 1.22.2
+	"js":   "55", // Node.js 15.8.0 (64bit)
+	"cs":   "79", // C# 10, .NET SDK 6.0
+	"hs":   "12", // Haskell GHC 8.10.1
+}
+
+// WebOptions configures a WebSubmitter.
+type WebOptions struct {
+	BaseURL string // e.g. https://codeforces.com
+	Handle  string
+	// Password is called for the password when a login is needed.
+	Password    func() (string, error)
+	CookieFile  string            // where the session is kept between runs, "" to not keep it
+	LanguageIDs map[string]string // extension to programTypeId, overriding DefaultLanguageIDs
+}
+
+// WebSubmitter submits through the Codeforces website, logging in with the
+// handle and password when the saved session has expired.
+type WebSubmitter struct {
+	opts    WebOptions
+	baseURL *url.URL
+	client  *http.Client
+	logger  *log.Logger
+}
+
+// ftaa and bfaa are browser fingerprints the website expects in its forms;
+// any well-formed values are accepted.
+const (
+	ftaa = "n3bhw2tdjz7x3ql9cm"
+	bfaa = "f1b3f18c715565b589b7823cda7448ce"
+)
+
+var (
+	csrfRe         = regexp.MustCompile(`name=["']X-Csrf-Token["']\s+content=["']([0-9a-f]+)["']|name=["']csrf_token["']\s+value=["']([0-9a-f]+)["']`)
+	handleRe       = regexp.MustCompile(`var handle = "([^"]*)"`)
+	submissionIDRe = regexp.MustCompile(`data-submission-id=["'](\d+)["']`)
+	formErrorRe    = regexp.MustCompile(`<span class=["']error for__(\w+)["']>([^<]*)</span>`)
+)
+
+func NewWebSubmitter(opts WebOptions, logger *log.Logger) (*WebSubmitter, error) {
+	base, err := url.Parse(strings.TrimRight(opts.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &WebSubmitter{
+		opts:    opts,
+		baseURL: base,
+		client:  &http.Client{Jar: jar},
+		logger:  logger,
+	}
+	s.loadCookies()
+	return s, nil
+}
+
+func (s *WebSubmitter) Submit(req Request) (int64, error) {
+	languageID, err := s.languageID(req.Language)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.ensureLoggedIn(); err != nil {
+		return 0, err
+	}
+
+	submitPath := fmt.Sprintf("/%s/%d/submit", req.Target.Kind, req.Target.ContestID)
+	if req.Target.Kind == "problemset" {
+		submitPath = "/problemset/submit"
+	}
+
+	page, err := s.get(submitPath)
+	if err != nil {
+		return 0, err
+	}
+	csrf, err := csrfToken(page)
+	if err != nil {
+		return 0, err
+	}
+
+	form := url.Values{
+		"csrf_token":            {csrf},
+		"ftaa":                  {ftaa},
+		"bfaa":                  {bfaa},
+		"action":                {"submitSolutionFormSubmitted"},
+		"programTypeId":         {languageID},
+		"source":                {string(req.Source)},
+		"tabSize":               {"4"},
+		"sourceFile":            {""},
+		"submittedProblemIndex": {req.Target.Index},
+	}
+	if req.Target.Kind == "problemset" {
+		form.Del("submittedProblemIndex")
+		form.Set("submittedProblemCode", fmt.Sprintf("%d%s", req.Target.ContestID, req.Target.Index))
+	}
+
+	s.logger.Printf("Submitting %s to %s %d%s", req.Language, req.Target.Kind, req.Target.ContestID, req.Target.Index)
+	page, err = s.post(submitPath+"?csrf_token="+csrf, form)
+	if err != nil {
+		return 0, err
+	}
+	s.saveCookies()
+
+	if m := formErrorRe.FindStringSubmatch(page); m != nil && strings.TrimSpace(m[2]) != "" {
+		return 0, fmt.Errorf("submission rejected: %s", html.UnescapeString(strings.TrimSpace(m[2])))
+	}
+	m := submissionIDRe.FindStringSubmatch(page)
+	if m == nil {
+		return 0, errors.New("submitted, but the submission id was not found on the status page")
+	}
+	return strconv.ParseInt(m[1], 10, 64)
+}
+
+func (s *WebSubmitter) languageID(language string) (string, error) {
+	if id, ok := s.opts.LanguageIDs[language]; ok {
+		return id, nil
+	}
+	if id, ok := DefaultLanguageIDs[language]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("no Codeforces language id for %q files, set one in submitLanguageIds", language)
+}
+
+// ensureLoggedIn logs in unless the saved session still belongs to the handle.
+func (s *WebSubmitter) ensureLoggedIn() error {
+	page, err := s.get("/enter")
+	if err != nil {
+		return err
+	}
+	if m := handleRe.FindStringSubmatch(page); m != nil && m[1] != "" {
+		if strings.EqualFold(m[1], s.opts.Handle) {
+			s.logger.Printf("Already logged in as %s", m[1])
+			return nil
+		}
+		s.logger.Printf("Logged in as %s instead of %s, logging in again", m[1], s.opts.Handle)
+	}
+
+	if s.opts.Handle == "" {
+		return errors.New("no handle configured")
+	}
+	csrf, err := csrfToken(page)
+	if err != nil {
+		return err
+	}
+	if s.opts.Password == nil {
+		return errors.New("login required but no password available")
+	}
+	password, err := s.opts.Password()
+	if err != nil {
+		return err
+	}
+
+	page, err = s.post("/enter", url.Values{
+		"csrf_token":    {csrf},
+		"ftaa":          {ftaa},
+		"bfaa":          {bfaa},
+		"action":        {"enter"},
+		"handleOrEmail": {s.opts.Handle},
+		"password":      {password},
+		"remember":      {"on"},
+	})
+	if err != nil {
+		return err
+	}
+	if m := handleRe.FindStringSubmatch(page); m == nil || m[1] == "" {
+		return fmt.Errorf("login as %s failed, check the handle and password", s.opts.Handle)
+	}
+	s.logger.Printf("Logged in as %s", s.opts.Handle)
+	s.saveCookies()
+	return nil
+}
+
+func csrfToken(page string) (string, error) {
+	m := csrfRe.FindStringSubmatch(page)
+	if m == nil {
+		return "", errors.New("CSRF token not found on the page")
+	}
+	if m[1] != "" {
+		return m[1], nil
+	}
+	return m[2], nil
+}
+
+func (s *WebSubmitter) get(path string) (string, error) {
+	resp, err := s.client.Get(s.baseURL.String() + path)
+	if err != nil {
+		return "", err
+	}
+	return readPage(resp)
+}
+
+func (s *WebSubmitter) post(path string, form url.Values) (string, error) {
+	resp, err := s.client.PostForm(s.baseURL.String()+path, form)
+	if err != nil {
+		return "", err
+	}
+	return readPage(resp)
+}
+
+func readPage(resp *http.Response) (string, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("%s %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status)
+	}
+	return string(body), nil
+}
+
+// savedCookie is the part of a cookie kept in the cookie file.
+type savedCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (s *WebSubmitter) loadCookies() {
+	if s.opts.CookieFile == "" {
+		return
+	}
+	data, err := os.ReadFile(s.opts.CookieFile)
+	if err != nil {
+		return
+	}
+	var saved []savedCookie
+	if err := json.Unmarshal(data, &saved); err != nil {
+		s.logger.Printf("WARN: ignoring unreadable cookie file %s: %v", s.opts.CookieFile, err)
+		return
+	}
+	cookies := make([]*http.Cookie, 0, len(saved))
+	for _, c := range saved {
+		cookies = append(cookies, &http.Cookie{Name: c.Name, Value: c.Value})
+	}
+	s.client.Jar.SetCookies(s.baseURL, cookies)
+}
+
+// saveCookies keeps the session cookies, readable only by the user since
+// they grant access to the account.
+func (s *WebSubmitter) saveCookies() {
+	if s.opts.CookieFile == "" {
+		return
+	}
+	var saved []savedCookie
+	for _, c := range s.client.Jar.Cookies(s.baseURL) {
+		saved = append(saved, savedCookie{Name: c.Name, Value: c.Value})
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.opts.CookieFile), 0o700); err != nil {
+		s.logger.Printf("WARN: failed to save cookies: %v", err)
+		return
+	}
+	if err := os.WriteFile(s.opts.CookieFile, data, 0o600); err != nil {
+		s.logger.Printf("WARN: failed to save cookies: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package submit
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+)
+
+const testCSRF = "0123456789abcdef0123456789abcdef"
+
+// standIn imitates the parts of the Codeforces website used for submitting.
+type standIn struct {
+	*httptest.Server
+	mu          sync.Mutex
+	logins      int
+	submissions []map[string]string
+}
+
+func newStandIn(t *testing.T) *standIn {
+	t.Helper()
+	s := &standIn{}
+	mux := http.NewServeMux()
+
+	page := func(w http.ResponseWriter, r *http.Request, extra string) {
+		handle := ""
+		if c, err := r.Cookie("JSESSIONID"); err == nil && c.Value == "session-tourist" {
+			handle = "tourist"
+		}
+		fmt.Fprintf(w, `<html><head><meta name="X-Csrf-Token" content="%s"/></head>
+<script>var handle = "%s";</script>%s</html>`, testCSRF, handle, extra)
+	}
+
+	mux.HandleFunc("/enter", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			r.ParseForm()
+			if r.Form.Get("csrf_token") == testCSRF && r.Form.Get("handleOrEmail") == "tourist" && r.Form.Get("password") == "secret" {
+				s.mu.Lock()
+				s.logins++
+				s.mu.Unlock()
+				http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "session-tourist", Path: "/"})
+				http.Redirect(w, r, "/", http.StatusFound)
+				return
+			}
+			page(w, r, `<span class="error for__password">Invalid handle/email or password</span>`)
+			return
+		}
+		page(w, r, "")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) { page(w, r, "") })
+	mux.HandleFunc("/contest/1234/submit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			page(w, r, "")
+			return
+		}
+		r.ParseForm()
+		if c, err := r.Cookie("JSESSIONID"); err != nil || c.Value != "session-tourist" {
+			http.Error(w, "not logged in", http.StatusForbidden)
+			return
+		}
+		form := map[string]string{}
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		s.mu.Lock()
+		s.submissions = append(s.submissions, form)
+		duplicate := len(s.submissions) > 1 && s.submissions[len(s.submissions)-2]["source"] == form["source"]
+		s.mu.Unlock()
+		if duplicate {
+			page(w, r, `<span class="error for__source">You have submitted exactly the same code before</span>`)
+			return
+		}
+		http.Redirect(w, r, "/contest/1234/my", http.StatusFound)
+	})
+	mux.HandleFunc("/contest/1234/my", func(w http.ResponseWriter, r *http.Request) {
+		page(w, r, `<tr data-submission-id="300000002"></tr><tr data-submission-id="300000001"></tr>`)
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Target
+	}{
+		{"https://codeforces.com/contest/1234/problem/A", Target{"contest", 1234, "A"}},
+		{"https://codeforces.com/gym/104000/problem/B2", Target{"gym", 104000, "B2"}},
+		{"https://codeforces.com/problemset/problem/1700/C", Target{"problemset", 1700, "C"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseTarget(tt.url)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseTarget(%q) = %+v, %v; want %+v", tt.url, got, err, tt.want)
+		}
+	}
+	if _, err := ParseTarget("https://atcoder.jp/contests/abc300/tasks/abc300_a"); err == nil {
+		t.Errorf("expected a non-Codeforces URL to fail")
+	}
+}
+
+func TestWebSubmitter_Submit(t *testing.T) {
+	server := newStandIn(t)
+	cookieFile := filepath.Join(t.TempDir(), "cookies.json")
+
+	passwordCalls := 0
+	opts := WebOptions{
+		BaseURL: server.URL,
+		Handle:  "tourist",
+		Password: func() (string, error) {
+			passwordCalls++
+			return "secret", nil
+		},
+		CookieFile:  cookieFile,
+		LanguageIDs: map[string]string{"py": "70"},
+	}
+	logger := log.New(os.Stderr, "TEST: ", 0)
+
+	s, err := NewWebSubmitter(opts, logger)
+	if err != nil {
+		t.Fatalf("NewWebSubmitter failed: %v", err)
+	}
+	id, err := s.Submit(Request{Target: Target{"contest", 1234, "A"}, Language: "py", Source: []byte("print(1)\n")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if id != 300000002 {
+		t.Errorf("expected submission id 300000002, got %d", id)
+	}
+
+	form := server.submissions[0]
+	if form["programTypeId"] != "70" || form["submittedProblemIndex"] != "A" || form["source"] != "print(1)\n" || form["csrf_token"] != testCSRF {
+		t.Errorf("unexpected submit form: %v", form)
+	}
+
+	// A new submitter reuses the saved session instead of logging in again.
+	s, err = NewWebSubmitter(opts, logger)
+	if err != nil {
+		t.Fatalf("NewWebSubmitter failed: %v", err)
+	}
+	_, err = s.Submit(Request{Target: Target{"contest", 1234, "A"}, Language: "py", Source: []byte("print(1)\n")})
+	if err == nil || err.Error() != "submission rejected: You have submitted exactly the same code before" {
+		t.Errorf("expected the duplicate to be rejected, got %v", err)
+	}
+	if passwordCalls != 1 || server.logins != 1 {
+		t.Errorf("expected a single login, got %d password prompts and %d logins", passwordCalls, server.logins)
+	}
+	if info, err := os.Stat(cookieFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected a private cookie file, got %v, %v", info, err)
+	}
+}
+
+func TestWebSubmitter_LoginFailure(t *testing.T) {
+	server := newStandIn(t)
+	s, err := NewWebSubmitter(WebOptions{
+		BaseURL:  server.URL,
+		Handle:   "tourist",
+		Password: func() (string, error) { return "wrong", nil },
+	}, log.New(os.Stderr, "TEST: ", 0))
+	if err != nil {
+		t.Fatalf("NewWebSubmitter failed: %v", err)
+	}
+
+	if _, err := s.Submit(Request{Target: Target{"contest", 1234, "A"}, Language: "cpp", Source: []byte("int main(){}")}); err == nil {
+		t.Errorf("expected the login to fail")
+	}
+	if len(server.submissions) != 0 {
+		t.Errorf("expected nothing to be submitted")
+	}
+}
+
+func TestPoller_Wait(t *testing.T) {
+	responses := []string{
+		`{"status":"OK","result":[]}`,
+		`{"status":"FAILED","comment":"handle: temporarily unavailable"}`,
+		`{"status":"OK","result":[{"id":300000002,"contestId":1234,"problem":{"contestId":1234,"index":"A"},"verdict":"TESTING","passedTestCount":3}]}`,
+		`{"status":"OK","result":[{"id":300000002,"contestId":1234,"problem":{"contestId":1234,"index":"A"},"verdict":"OK","passedTestCount":42,"timeConsumedMillis":46}]}`,
+	}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user.status" || r.URL.Query().Get("handle") != "tourist" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, responses[min(calls, len(responses)-1)])
+		calls++
+	}))
+	defer server.Close()
+
+	logger := log.New(os.Stderr, "TEST: ", 0)
+	api := cfapi.NewClient(server.URL, logger)
+	api.SetInterval(0)
+
+	p := NewPoller(api, 0, time.Minute, logger)
+	var progress []string
+	p.SetProgress(func(sub *cfapi.Submission) {
+		progress = append(progress, fmt.Sprintf("%s/%d", sub.Verdict, sub.PassedTestCount))
+	})
+	sub, err := p.Wait("tourist", 300000002)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if sub.Verdict != "OK" || sub.PassedTestCount != 42 || calls != 4 {
+		t.Errorf("unexpected result after %d calls: %+v", calls, sub)
+	}
+	if want := []string{"TESTING/3", "OK/42"}; fmt.Sprint(progress) != fmt.Sprint(want) {
+		t.Errorf("progress = %v, want %v", progress, want)
+	}
+}
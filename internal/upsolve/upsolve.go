@@ -0,0 +1,138 @@
+package upsolve
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+// Mark is how a problem was marked with 'upsolve mark'.
+type Mark string
+
+const (
+	MarkDone Mark = "done"
+	MarkTodo Mark = "todo"
+	MarkSkip Mark = "skip"
+)
+
+func ParseMark(s string) (Mark, error) {
+	switch m := Mark(s); m {
+	case MarkDone, MarkTodo, MarkSkip:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid mark %q (expected done, todo or skip)", s)
+}
+
+// Passed reports whether the problem ever passed all its tests locally or
+// was accepted on Codeforces. A run without tests doesn't count.
+func Passed(meta *directorymanager.Metadata) bool {
+	if meta.PassedAt != nil || meta.LastRun != nil && meta.LastRun.Verdict == "OK" && meta.LastRun.Total > 0 {
+		return true
+	}
+	for _, sub := range meta.Submissions {
+		if sub.Verdict == "OK" {
+			return true
+		}
+	}
+	return false
+}
+
+// Item is a problem left to upsolve.
+type Item struct {
+	Entry  directorymanager.ProblemEntry
+	Reason string // "marked todo" or "not passed"
+	Rating int    // from problem.json or the catalog, 0 when unknown
+}
+
+// Catalog holds what is known about contests and problems from the
+// Codeforces API, used to order and annotate the list.
+type Catalog struct {
+	ContestStart map[int]time.Time
+	Ratings      map[string]int // by problem id, e.g. "1985C"
+}
+
+// NewCatalog indexes the contest list and problemset, either of which may
+// be nil when no copy is available.
+func NewCatalog(contests []cfapi.Contest, set *cfapi.ProblemSet) Catalog {
+	c := Catalog{ContestStart: map[int]time.Time{}, Ratings: map[string]int{}}
+	for _, contest := range contests {
+		if contest.StartTimeSeconds > 0 {
+			c.ContestStart[contest.ID] = time.Unix(contest.StartTimeSeconds, 0)
+		}
+	}
+	if set != nil {
+		for _, p := range set.Problems {
+			if p.Rating > 0 {
+				c.Ratings[ProblemID(p.ContestID, p.Index)] = p.Rating
+			}
+		}
+	}
+	return c
+}
+
+// ProblemID names a problem the way Codeforces does, e.g. "1985C".
+func ProblemID(contestID int, index string) string {
+	return strconv.Itoa(contestID) + index
+}
+
+var problemIDRe = regexp.MustCompile(`^(\d+)([A-Za-z]\d*)$`)
+
+// ParseProblemID splits a problem id such as "1985C" or "1980F2".
+func ParseProblemID(id string) (int, string, bool) {
+	m := problemIDRe.FindStringSubmatch(id)
+	if m == nil {
+		return 0, "", false
+	}
+	contestID, _ := strconv.Atoi(m[1])
+	return contestID, strings.ToUpper(m[2]), true
+}
+
+// List returns the problems marked todo and the ones never passed that are
+// not marked done or skipped, the most recent contest first. Contests with
+// an unknown date come last, ordered by id, which grows with time.
+func List(entries []directorymanager.ProblemEntry, catalog Catalog) []Item {
+	var items []Item
+	for _, entry := range entries {
+		var reason string
+		switch Mark(entry.Metadata.Upsolve) {
+		case MarkTodo:
+			reason = "marked todo"
+		case MarkDone, MarkSkip:
+			continue
+		default:
+			if Passed(entry.Metadata) {
+				continue
+			}
+			reason = "not passed"
+		}
+		p := entry.Problem
+		rating := entry.Metadata.Rating
+		if rating == 0 {
+			rating = catalog.Ratings[ProblemID(p.ContestCode, p.Index)]
+		}
+		items = append(items, Item{Entry: entry, Reason: reason, Rating: rating})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Entry.Problem, items[j].Entry.Problem
+		if a.ContestCode != b.ContestCode {
+			startA, okA := catalog.ContestStart[a.ContestCode]
+			startB, okB := catalog.ContestStart[b.ContestCode]
+			if okA != okB {
+				return okA
+			}
+			if okA && !startA.Equal(startB) {
+				return startA.After(startB)
+			}
+			return a.ContestCode > b.ContestCode
+		}
+		return a.Index < b.Index
+	})
+	return items
+}
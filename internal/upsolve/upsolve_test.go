@@ -0,0 +1,70 @@
+package upsolve
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/cfapi"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+func entry(contest int, index string, meta directorymanager.Metadata) directorymanager.ProblemEntry {
+	return directorymanager.ProblemEntry{
+		Problem:  directorymanager.Problem{ContestCode: contest, Index: index},
+		Metadata: &meta,
+	}
+}
+
+func TestList(t *testing.T) {
+	passed := time.Date(2024, 6, 10, 15, 0, 0, 0, time.UTC)
+	entries := []directorymanager.ProblemEntry{
+		entry(1985, "A", directorymanager.Metadata{PassedAt: &passed}),
+		entry(1985, "C", directorymanager.Metadata{LastRun: &directorymanager.RunRecord{Verdict: "WA", Total: 2}}),
+		entry(1985, "B", directorymanager.Metadata{LastRun: &directorymanager.RunRecord{Verdict: "OK"}}), // no tests
+		entry(1985, "D", directorymanager.Metadata{Upsolve: "skip"}),
+		entry(1980, "F", directorymanager.Metadata{PassedAt: &passed, Upsolve: "todo"}),
+		entry(1980, "E", directorymanager.Metadata{Upsolve: "done"}),
+		entry(1990, "A", directorymanager.Metadata{Submissions: []directorymanager.SubmissionRecord{{Verdict: "OK"}}}),
+		entry(1000, "A", directorymanager.Metadata{}),
+		entry(2000, "A", directorymanager.Metadata{Rating: 1500}),
+	}
+
+	// 1980 took place after 1985 here, 1000 and 2000 have no known date.
+	catalog := NewCatalog(
+		[]cfapi.Contest{
+			{ID: 1985, StartTimeSeconds: 1718000000},
+			{ID: 1980, StartTimeSeconds: 1719000000},
+		},
+		&cfapi.ProblemSet{Problems: []cfapi.Problem{{ContestID: 1985, Index: "C", Rating: 1300}}},
+	)
+
+	items := List(entries, catalog)
+	var got []string
+	for _, item := range items {
+		got = append(got, ProblemID(item.Entry.Problem.ContestCode, item.Entry.Problem.Index)+" "+item.Reason)
+	}
+	want := []string{"1980F marked todo", "1985B not passed", "1985C not passed", "2000A not passed", "1000A not passed"}
+	if len(got) != len(want) {
+		t.Fatalf("List = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("List = %q, want %q", got, want)
+		}
+	}
+	if items[2].Rating != 1300 || items[1].Rating != 0 || items[3].Rating != 1500 {
+		t.Errorf("unexpected ratings: %d, %d, %d", items[2].Rating, items[1].Rating, items[3].Rating)
+	}
+}
+
+func TestParseProblemID(t *testing.T) {
+	if id, index, ok := ParseProblemID("1980f2"); !ok || id != 1980 || index != "F2" {
+		t.Errorf("ParseProblemID(1980f2) = %d, %q, %v", id, index, ok)
+	}
+	if _, _, ok := ParseProblemID("./1985/C"); ok {
+		t.Errorf("expected a path not to be a problem id")
+	}
+	if _, err := ParseMark("later"); err == nil {
+		t.Errorf("expected an unknown mark to fail")
+	}
+}
@@ -0,0 +1,135 @@
+package virtual
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+// Session is a virtual contest, timed like the real one.
+type Session struct {
+	ContestID int       `json:"contestId"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+func NewSession(contestID int, start time.Time, duration time.Duration) *Session {
+	return &Session{ContestID: contestID, Start: start, End: start.Add(duration)}
+}
+
+// Running reports whether the contest is under way at now.
+func (s *Session) Running(now time.Time) bool {
+	return !now.Before(s.Start) && now.Before(s.End)
+}
+
+func (s *Session) Remaining(now time.Time) time.Duration {
+	return max(s.End.Sub(now), 0)
+}
+
+// Load reads the session saved at path. It returns nil without an error
+// when no session was started.
+func Load(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func Save(path string, s *Session) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Stamp records in the metadata of a problem of the contest that it was
+// opened and, if solved, that it passed all tests, keeping the earliest
+// times. Stamps of an earlier virtual contest are replaced. It reports
+// whether anything changed.
+func (s *Session) Stamp(meta *directorymanager.Metadata, now time.Time, solved bool) bool {
+	changed := false
+	if meta.Virtual == nil || !meta.Virtual.Start.Equal(s.Start) {
+		meta.Virtual = &directorymanager.VirtualRecord{Start: s.Start, OpenedAt: now}
+		changed = true
+	}
+	if solved && meta.Virtual.SolvedAt == nil {
+		solvedAt := now
+		meta.Virtual.SolvedAt = &solvedAt
+		changed = true
+	}
+	return changed
+}
+
+// Line is the pacing of one problem in a virtual contest, measured from the
+// start of the contest.
+type Line struct {
+	Index  string
+	Name   string
+	Opened time.Duration
+	Solved time.Duration // 0 when not solved
+}
+
+// Summary collects the pacing of the problems stamped during the session,
+// ordered by problem index.
+func Summary(s *Session, entries []directorymanager.ProblemEntry) []Line {
+	var lines []Line
+	for _, entry := range entries {
+		record := entry.Metadata.Virtual
+		if entry.Problem.ContestCode != s.ContestID || record == nil || !record.Start.Equal(s.Start) {
+			continue
+		}
+		line := Line{
+			Index:  entry.Problem.Index,
+			Name:   entry.Metadata.Name,
+			Opened: record.OpenedAt.Sub(s.Start),
+		}
+		if record.SolvedAt != nil {
+			line.Solved = record.SolvedAt.Sub(s.Start)
+		}
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Index < lines[j].Index })
+	return lines
+}
+
+// WriteSummary prints the pacing table: when each problem was opened and
+// solved, and the time spent on it.
+func WriteSummary(w io.Writer, lines []Line) {
+	fmt.Fprintf(w, "%-8s%-10s%-10s%s\n", "Problem", "Opened", "Solved", "Time")
+	solved := 0
+	for _, line := range lines {
+		solvedAt, spent := "-", "-"
+		if line.Solved > 0 {
+			solvedAt, spent = FormatDuration(line.Solved), FormatDuration(line.Solved-line.Opened)
+			solved++
+		}
+		fmt.Fprintf(w, "%-8s%-10s%-10s%s\n", line.Index, FormatDuration(line.Opened), solvedAt, spent)
+	}
+	fmt.Fprintf(w, "Solved %d of %d problems opened\n", solved, len(lines))
+}
+
+// FormatDuration formats d as h:mm:ss, the way contest times are shown.
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
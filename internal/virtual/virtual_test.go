@@ -0,0 +1,106 @@
+package virtual
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/ccparser"
+	"github.com/PriyanshuSharma23/codeforces-cli/internal/directorymanager"
+)
+
+var start = time.Date(2024, 6, 10, 14, 35, 0, 0, time.UTC)
+
+func TestSession_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "virtual.json")
+
+	if s, err := Load(path); s != nil || err != nil {
+		t.Fatalf("expected no session before one is saved, got %+v, %v", s, err)
+	}
+
+	s := NewSession(1985, start, 2*time.Hour)
+	if err := Save(path, s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.ContestID != 1985 || !loaded.Start.Equal(start) || !loaded.End.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("unexpected session: %+v", loaded)
+	}
+
+	if !s.Running(start.Add(time.Hour)) || s.Running(start.Add(2*time.Hour)) {
+		t.Errorf("expected the session to run for two hours")
+	}
+	if got := s.Remaining(start.Add(90 * time.Minute)); got != 30*time.Minute {
+		t.Errorf("Remaining = %s, want 30m", got)
+	}
+	if got := s.Remaining(start.Add(3 * time.Hour)); got != 0 {
+		t.Errorf("Remaining after the end = %s, want 0", got)
+	}
+}
+
+func TestSession_Stamp(t *testing.T) {
+	s := NewSession(1985, start, 2*time.Hour)
+	meta := &directorymanager.Metadata{}
+
+	if !s.Stamp(meta, start.Add(2*time.Minute), false) {
+		t.Fatalf("expected the first stamp to change the metadata")
+	}
+	if s.Stamp(meta, start.Add(5*time.Minute), false) {
+		t.Errorf("expected opening the problem again to keep the first time")
+	}
+	if !s.Stamp(meta, start.Add(9*time.Minute), true) {
+		t.Errorf("expected the first pass to be stamped")
+	}
+	if s.Stamp(meta, start.Add(20*time.Minute), true) {
+		t.Errorf("expected passing again to keep the first time")
+	}
+
+	record := meta.Virtual
+	if !record.OpenedAt.Equal(start.Add(2*time.Minute)) || record.SolvedAt == nil || !record.SolvedAt.Equal(start.Add(9*time.Minute)) {
+		t.Errorf("unexpected record: %+v", record)
+	}
+
+	// A later virtual contest starts over.
+	next := NewSession(1985, start.Add(48*time.Hour), 2*time.Hour)
+	next.Stamp(meta, next.Start.Add(time.Minute), false)
+	if !meta.Virtual.Start.Equal(next.Start) || meta.Virtual.SolvedAt != nil {
+		t.Errorf("expected the stamps of the earlier contest to be replaced: %+v", meta.Virtual)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	s := NewSession(1985, start, 2*time.Hour)
+	solved := start.Add(9 * time.Minute)
+	entry := func(contest int, index string, record *directorymanager.VirtualRecord) directorymanager.ProblemEntry {
+		return directorymanager.ProblemEntry{
+			Problem:  directorymanager.Problem{ContestCode: contest, Index: index},
+			Metadata: &directorymanager.Metadata{CCProblem: ccparser.CCProblem{Name: index + ". Problem"}, Virtual: record},
+		}
+	}
+	entries := []directorymanager.ProblemEntry{
+		entry(1985, "B", &directorymanager.VirtualRecord{Start: start, OpenedAt: start.Add(10 * time.Minute)}),
+		entry(1985, "A", &directorymanager.VirtualRecord{Start: start, OpenedAt: start.Add(2 * time.Minute), SolvedAt: &solved}),
+		entry(1985, "C", nil),
+		entry(1986, "A", &directorymanager.VirtualRecord{Start: start, OpenedAt: start}),
+	}
+
+	lines := Summary(s, entries)
+	if len(lines) != 2 || lines[0].Index != "A" || lines[0].Solved != 9*time.Minute || lines[1].Index != "B" || lines[1].Solved != 0 {
+		t.Fatalf("unexpected summary: %+v", lines)
+	}
+
+	var buf bytes.Buffer
+	WriteSummary(&buf, lines)
+	want := `Problem Opened    Solved    Time
+A       0:02:00   0:09:00   0:07:00
+B       0:10:00   -         -
+Solved 1 of 2 problems opened
+`
+	if buf.String() != want {
+		t.Errorf("WriteSummary =\n%s\nwant\n%s", buf.String(), want)
+	}
+}